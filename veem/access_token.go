@@ -1,12 +1,16 @@
 package veem
 
 import (
+	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type AccessTokenResponse struct {
@@ -17,16 +21,98 @@ type AccessTokenResponse struct {
 	UserID      string `json:"user_id"`
 	AccountID   string `json:"account_id"`
 	Username    string `json:"user_name"`
+	// Only issued to partner apps by the authorization code flow.
+	RefreshToken string `json:"refresh_token,omitempty"`
 
 	// Calculated at retrieval
 	ExpiresAt time.Time
 }
 
-func (c *client) getAccessToken() (*AccessTokenResponse, error) {
+// fetchToken returns a token from the TokenStore if it holds a usable one,
+// otherwise it retrieves a new token and saves it to the store. Failures are
+// returned as an *AuthError.
+func (c *client) fetchToken(ctx context.Context) (*AccessTokenResponse, error) {
+	tok, err := c.loadOrGetToken(ctx, false)
+	if err != nil {
+		return nil, &AuthError{Err: err}
+	}
+	return tok, nil
+}
+
+// loadOrGetToken returns the token held by the TokenStore if it is usable,
+// unless force is set, and otherwise retrieves and saves a new one.
+func (c *client) loadOrGetToken(ctx context.Context, force bool) (*AccessTokenResponse, error) {
+	store := c.opts.TokenStore
+	if store == nil {
+		return c.getAccessToken(ctx, nil)
+	}
+	key := c.tokenStoreKey()
+	unlock, err := store.Lock(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	tok, err := store.Load(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !force && tok != nil && time.Now().Add(tokenRefreshWindow).Before(tok.ExpiresAt) && !c.tokens.Rejected(tok) {
+		return tok, nil
+	}
+	if tok, err = c.getAccessToken(ctx, tok); err != nil {
+		return nil, err
+	}
+	return tok, store.Save(ctx, key, tok)
+}
+
+// tokenStoreKey identifies the credentials, scopes and API a token is issued
+// for. Tokens acting on behalf of a user are keyed by the user instead of the
+// scopes, New refuses a TokenStore for user tokens without a UserID.
+func (c *client) tokenStoreKey() string {
+	if c.opts.UserToken != nil {
+		return fmt.Sprintf("%s:%s@%s", c.opts.ClientID, c.opts.UserToken.UserID, c.apiURL.String())
+	}
+	return fmt.Sprintf("%s@%s#%s", c.opts.ClientID, c.apiURL.String(), c.opts.scope())
+}
+
+// getAccessToken retrieves a new access token. Clients acting on behalf of a
+// user refresh the user's token, all others use their client credentials.
+// The refresh token of stored, the token last saved to the TokenStore, is
+// preferred to the one held in memory as another process may have rotated it.
+func (c *client) getAccessToken(ctx context.Context, stored *AccessTokenResponse) (*AccessTokenResponse, error) {
+	if c.opts.UserToken != nil {
+		if stored != nil && stored.RefreshToken != "" {
+			return c.refreshAccessToken(ctx, stored.RefreshToken)
+		}
+		return c.refreshAccessToken(ctx, c.tokens.Last().RefreshToken)
+	}
 	form := url.Values{}
 	form.Add("grant_type", "client_credentials")
-	form.Add("scope", "all")
-	req, err := c.newRequest(http.MethodPost, "oauth/token", strings.NewReader(form.Encode()))
+	form.Add("scope", c.opts.scope())
+	return c.requestToken(ctx, form)
+}
+
+// refreshAccessToken exchanges a refresh token for a new access token. The
+// refresh token is carried over if the API does not rotate it.
+func (c *client) refreshAccessToken(ctx context.Context, refreshToken string) (*AccessTokenResponse, error) {
+	if refreshToken == "" {
+		return nil, errors.New("the user token has expired and has no refresh token")
+	}
+	form := url.Values{}
+	form.Add("grant_type", "refresh_token")
+	form.Add("refresh_token", refreshToken)
+	res, err := c.requestToken(ctx, form)
+	if err != nil {
+		return nil, err
+	}
+	if res.RefreshToken == "" {
+		res.RefreshToken = refreshToken
+	}
+	return res, nil
+}
+
+func (c *client) requestToken(ctx context.Context, form url.Values) (*AccessTokenResponse, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "oauth/token", strings.NewReader(form.Encode()))
 	if err != nil {
 		return nil, err
 	}
@@ -35,10 +121,12 @@ func (c *client) getAccessToken() (*AccessTokenResponse, error) {
 	))
 	req.Header.Add("Accept", "application/json")
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	// Never reuse a caller-supplied request ID for the token exchange.
+	req.Header.Set(requestIDHeader, uuid.New().String())
 	res := &AccessTokenResponse{}
 	if err := c.doInto(req, res); err != nil {
 		return nil, err
 	}
-	res.ExpiresAt = time.Now().Add(time.Duration(res.ExpiresIn) * time.Millisecond)
+	res.ExpiresAt = time.Now().Add(time.Duration(res.ExpiresIn) * time.Second)
 	return res, nil
 }
@@ -0,0 +1,106 @@
+package veem
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTokenServer serves /oauth/token with a token named after the grant, and
+// answers every other request with an empty object.
+func newTokenServer(t *testing.T, refresh func(token string) (string, bool)) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth/token" {
+			w.Write([]byte(`{}`))
+			return
+		}
+		r.ParseForm()
+		switch r.PostForm.Get("grant_type") {
+		case "refresh_token":
+			next, ok := refresh(r.PostForm.Get("refresh_token"))
+			if !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":"invalid_grant"}`))
+				return
+			}
+			fmt.Fprintf(w, `{"access_token":"user","expires_in":3600,"refresh_token":%q}`, next)
+		default:
+			fmt.Fprintf(w, `{"access_token":%q,"expires_in":3600}`, r.PostForm.Get("scope"))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestUserTokenRefreshUsesStoredToken(t *testing.T) {
+	// Another process already rotated r1 to r2, so only r2 is accepted.
+	srv := newTokenServer(t, func(token string) (string, bool) { return "r3", token == "r2" })
+	store := NewMemoryTokenStore()
+	expired := time.Now().Add(-time.Minute)
+	opts := &ClientOptions{
+		BaseURL:    srv.URL,
+		ClientID:   "id",
+		UserToken:  &AccessTokenResponse{AccessToken: "old", UserID: "u", RefreshToken: "r1", ExpiresAt: expired},
+		TokenStore: store,
+		LazyAuth:   true,
+	}
+	c, err := New(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := c.(*client).tokenStoreKey()
+	store.Save(context.Background(), key, &AccessTokenResponse{AccessToken: "other", RefreshToken: "r2", ExpiresAt: expired})
+	if err := c.Authenticate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	saved, _ := store.Load(context.Background(), key)
+	if saved.RefreshToken != "r3" {
+		t.Errorf("stored refresh token = %q, want r3", saved.RefreshToken)
+	}
+}
+
+func TestTokenStoreKeyedByScope(t *testing.T) {
+	srv := newTokenServer(t, func(string) (string, bool) { return "", false })
+	store := NewMemoryTokenStore()
+	var wg sync.WaitGroup
+	for _, scopes := range [][]string{{"payments"}, {"contacts"}, nil} {
+		wg.Add(1)
+		go func(scopes []string) {
+			defer wg.Done()
+			c, err := New(&ClientOptions{BaseURL: srv.URL, ClientID: "id", Scopes: scopes, TokenStore: store})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			tok, _ := c.AccessToken(context.Background())
+			if want := c.(*client).opts.scope(); tok.AccessToken != want {
+				t.Errorf("token for scope %q = %q", want, tok.AccessToken)
+			}
+		}(scopes)
+	}
+	wg.Wait()
+}
+
+func TestTokenStoreRequiresUserID(t *testing.T) {
+	srv := newTokenServer(t, func(string) (string, bool) { return "r2", true })
+	tests := []struct {
+		name    string
+		opts    *ClientOptions
+		wantErr bool
+	}{
+		{"no user ID", &ClientOptions{UserToken: &AccessTokenResponse{RefreshToken: "r1"}, TokenStore: NewMemoryTokenStore()}, true},
+		{"user ID", &ClientOptions{UserToken: &AccessTokenResponse{UserID: "u", RefreshToken: "r1"}, TokenStore: NewMemoryTokenStore()}, false},
+		{"no store", &ClientOptions{UserToken: &AccessTokenResponse{RefreshToken: "r1"}}, false},
+	}
+	for _, tt := range tests {
+		tt.opts.BaseURL, tt.opts.ClientID, tt.opts.LazyAuth = srv.URL, "id", true
+		if _, err := New(tt.opts); (err != nil) != tt.wantErr {
+			t.Errorf("%s: New() error = %v, want error %t", tt.name, err, tt.wantErr)
+		}
+	}
+}
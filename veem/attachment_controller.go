@@ -2,6 +2,7 @@ package veem
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -14,9 +15,9 @@ import (
 // to Invoices and Payments.
 type AttachmentController interface {
 	// Uploads an external attachment for a Payment or Invoice
-	Upload(filename string) (*Attachment, error)
+	Upload(ctx context.Context, filename string) (*Attachment, error)
 	// Downloads the referenced file
-	Download(name, referenceID string) (io.ReadCloser, error)
+	Download(ctx context.Context, name, referenceID string) (io.ReadCloser, error)
 }
 
 type attachmentController struct{ *client }
@@ -34,7 +35,7 @@ type Attachment struct {
 	Type        AttachmentType `json:"type"`
 }
 
-func (a *attachmentController) Upload(filename string) (*Attachment, error) {
+func (a *attachmentController) Upload(ctx context.Context, filename string) (*Attachment, error) {
 	f, err := os.Open(filename)
 	if err != nil {
 		return nil, err
@@ -52,7 +53,7 @@ func (a *attachmentController) Upload(filename string) (*Attachment, error) {
 	if err := writer.Close(); err != nil {
 		return nil, err
 	}
-	req, err := a.newRequest(http.MethodPost, "veem/v1.1/attachments", &body)
+	req, err := a.newRequest(ctx, http.MethodPost, "veem/v1.1/attachments", &body)
 	if err != nil {
 		return nil, err
 	}
@@ -61,9 +62,9 @@ func (a *attachmentController) Upload(filename string) (*Attachment, error) {
 	return res, a.doIntoWithAuth(req, res)
 }
 
-func (a *attachmentController) Download(name, referenceID string) (io.ReadCloser, error) {
+func (a *attachmentController) Download(ctx context.Context, name, referenceID string) (io.ReadCloser, error) {
 	ep := fmt.Sprintf("veem/v1.1/attachments?name=%s&referenceId=%s", name, referenceID)
-	req, err := a.newRequest(http.MethodGet, ep, nil)
+	req, err := a.newRequest(ctx, http.MethodGet, ep, nil)
 	if err != nil {
 		return nil, err
 	}
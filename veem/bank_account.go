@@ -0,0 +1,327 @@
+package veem
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	ifscPattern  = regexp.MustCompile(`^[A-Z]{4}0[A-Z0-9]{6}$`)
+	swiftPattern = regexp.MustCompile(`^[A-Z]{4}([A-Z]{2})[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+	ibanPattern  = regexp.MustCompile(`^([A-Z]{2})[0-9]{2}[A-Z0-9]{11,30}$`)
+)
+
+// ibanLengths is the length of the IBANs of each country in the ISO 13616
+// registry.
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16, "BG": 22,
+	"BH": 22, "BI": 27, "BR": 29, "BY": 28, "CH": 21, "CR": 22, "CY": 28, "CZ": 24,
+	"DE": 22, "DJ": 27, "DK": 18, "DO": 28, "EE": 20, "EG": 29, "ES": 24, "FI": 18,
+	"FK": 18, "FO": 18, "FR": 27, "GB": 22, "GE": 22, "GI": 23, "GL": 18, "GR": 27,
+	"GT": 28, "HR": 21, "HU": 28, "IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27,
+	"JO": 30, "KW": 30, "KZ": 20, "LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20,
+	"LV": 21, "LY": 25, "MC": 27, "MD": 24, "ME": 22, "MK": 19, "MN": 20, "MR": 27,
+	"MT": 31, "MU": 30, "NI": 28, "NL": 18, "NO": 15, "OM": 23, "PK": 24, "PL": 28,
+	"PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22, "RU": 33, "SA": 24, "SC": 31,
+	"SD": 18, "SE": 24, "SI": 19, "SK": 24, "SM": 27, "SO": 23, "ST": 25, "SV": 28,
+	"TL": 23, "TN": 24, "TR": 26, "UA": 29, "VA": 22, "VG": 24, "XK": 20, "YE": 30,
+}
+
+// ibanCountries maps territories that use the IBANs of another country to
+// that country.
+var ibanCountries = map[string]string{
+	"JE": "GB", "GG": "GB", "IM": "GB",
+	"GF": "FR", "GP": "FR", "MQ": "FR", "RE": "FR", "YT": "FR", "PM": "FR",
+	"BL": "FR", "MF": "FR", "NC": "FR", "PF": "FR", "WF": "FR", "TF": "FR",
+	"AX": "FI",
+}
+
+// bankAccountFields are the fields of an account that may be listed in
+// CountryCurrentMap.BankFields, by JSON name.
+func bankAccountFields(b *BankAccount) map[string]string {
+	address := ""
+	if b.BankAddress != nil && *b.BankAddress != (Address{}) {
+		address = "set"
+	}
+	return map[string]string{
+		"bankAccountNumber":     b.AccountNumber,
+		"routingNumber":         b.RoutingNumber,
+		"bankName":              b.BankName,
+		"bankAddress":           address,
+		"bankCnaps":             b.BankCNaps,
+		"bankCode":              b.BankCode,
+		"bankIfscBranchCode":    b.BankIFSCBranchCode,
+		"bankInstitutionNumber": b.BankInstitutionNumber,
+		"beneficiaryName":       b.BeneficiaryName,
+		"branchCode":            b.BranchCode,
+		"bsbBankCode":           b.BSBBankCode,
+		"clabe":                 b.CLABE,
+		"iban":                  b.IBAN,
+		"sortCode":              b.SortCode,
+		"swiftBic":              b.SwiftBIC,
+		"transitCode":           b.TransitCode,
+	}
+}
+
+// bankFieldAliases maps the names of the bank fields that may appear in
+// CountryCurrentMap.BankFields, and other names for them, in lower case to
+// their JSON name.
+var bankFieldAliases = map[string]string{
+	"accountnumber":         "bankAccountNumber",
+	"bankaccountnumber":     "bankAccountNumber",
+	"routingnumber":         "routingNumber",
+	"abaroutingnumber":      "routingNumber",
+	"bankname":              "bankName",
+	"bankaddress":           "bankAddress",
+	"bankcnaps":             "bankCnaps",
+	"cnaps":                 "bankCnaps",
+	"bankcode":              "bankCode",
+	"bankifscbranchcode":    "bankIfscBranchCode",
+	"bankifsccode":          "bankIfscBranchCode",
+	"ifsc":                  "bankIfscBranchCode",
+	"ifsccode":              "bankIfscBranchCode",
+	"bankinstitutionnumber": "bankInstitutionNumber",
+	"institutionnumber":     "bankInstitutionNumber",
+	"beneficiaryname":       "beneficiaryName",
+	"branchcode":            "branchCode",
+	"bsbbankcode":           "bsbBankCode",
+	"bsb":                   "bsbBankCode",
+	"bsbcode":               "bsbBankCode",
+	"clabe":                 "clabe",
+	"iban":                  "iban",
+	"sortcode":              "sortCode",
+	"swiftbic":              "swiftBic",
+	"swift":                 "swiftBic",
+	"swiftcode":             "swiftBic",
+	"bic":                   "swiftBic",
+	"transitcode":           "transitCode",
+	"transitnumber":         "transitCode",
+}
+
+// bankDescriptionFields may be given for any country even when they are not
+// listed in its bank fields.
+var bankDescriptionFields = map[string]bool{
+	"bankName":        true,
+	"bankAddress":     true,
+	"beneficiaryName": true,
+}
+
+// ValidateBankAccount checks the account against the bank fields of its
+// country and currency. It reports fields the country requires but are
+// missing, account fields the country does not use, fields whose format or
+// check digits are wrong, and bank fields of the country it does not know. The country of a SWIFT/BIC is not compared to
+// the account's, as some territories use the BICs of another country.
+func (m *Metadata) ValidateBankAccount(account *BankAccount) []*FieldError {
+	problems := make([]*FieldError, 0)
+	report := func(field, code, format string, args ...interface{}) {
+		problems = append(problems, &FieldError{Field: field, Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+	if account == nil {
+		report("bankAccount", ProblemRequired, "a bank account is required")
+		return problems
+	}
+
+	country, currency := account.ISOCountryCode, account.CurrencyCode
+	var meta *CountryCurrentMap
+	switch {
+	case country == "":
+		report("isoCountryCode", ProblemRequired, "the bank country is required")
+	case !country.Valid():
+		report("isoCountryCode", ProblemInvalid, "%q is not an ISO 3166 country code", string(country))
+	default:
+		var ok bool
+		if meta, ok = m.Country(country); !ok {
+			report("isoCountryCode", ProblemUnsupported, "Veem does not support %s", country.Name())
+		}
+	}
+	switch {
+	case currency == "":
+		report("currencyCode", ProblemRequired, "the account currency is required")
+	case !currency.Valid():
+		report("currencyCode", ProblemInvalid, "%q is not an ISO 4217 currency code", string(currency))
+	case meta != nil && !meta.CanReceive(currency):
+		report("currencyCode", ProblemUnsupported, "accounts in %s cannot receive %s, supported currencies are %s",
+			country.Name(), currency, joinCurrencies(meta.ReceivingCurrencies))
+	}
+
+	values := bankAccountFields(account)
+	if meta != nil && len(meta.BankFields) > 0 {
+		required := make(map[string]bool, len(meta.BankFields))
+		unknown := false
+		for _, name := range meta.BankFields {
+			f, ok := bankFieldAliases[strings.ToLower(name)]
+			if !ok {
+				report(name, ProblemUnknown, "%s is listed for accounts in %s but cannot be checked", name, country.Name())
+				unknown = true
+				continue
+			}
+			required[f] = true
+			if strings.TrimSpace(values[f]) == "" {
+				report(f, ProblemRequired, "%s is required for accounts in %s", f, country.Name())
+			}
+		}
+		// An unknown field may be any of those given, so none is reported as
+		// extraneous.
+		if !unknown {
+			names := make([]string, 0, len(values))
+			for f := range values {
+				names = append(names, f)
+			}
+			sort.Strings(names)
+			for _, f := range names {
+				if values[f] != "" && !required[f] && !bankDescriptionFields[f] {
+					report(f, ProblemExtraneous, "%s is not used for accounts in %s", f, country.Name())
+				}
+			}
+		}
+	}
+
+	check := func(field, value string, validate func(string) error) {
+		if value == "" {
+			return
+		}
+		if err := validate(value); err != nil {
+			report(field, ProblemInvalid, "%s", err)
+		}
+	}
+	check("iban", account.IBAN, func(s string) error {
+		if err := ValidateIBAN(s); err != nil {
+			return err
+		}
+		want := strings.ToUpper(string(country))
+		if parent, ok := ibanCountries[want]; ok {
+			want = parent
+		}
+		if cc := normalizeBankCode(s)[:2]; country.Valid() && cc != want {
+			return fmt.Errorf("IBAN is for %s, not %s", cc, country)
+		}
+		return nil
+	})
+	check("swiftBic", account.SwiftBIC, ValidateSWIFTBIC)
+	check("clabe", account.CLABE, ValidateCLABE)
+	check("bankIfscBranchCode", account.BankIFSCBranchCode, ValidateIFSC)
+	if strings.EqualFold(string(country), "US") {
+		check("routingNumber", account.RoutingNumber, ValidateABARoutingNumber)
+	}
+	return problems
+}
+
+// ValidateBankAccount checks the account against the cached metadata, see
+// Metadata.ValidateBankAccount.
+func (m *MetadataCache) ValidateBankAccount(ctx context.Context, account *BankAccount) ([]*FieldError, error) {
+	meta, err := m.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return meta.ValidateBankAccount(account), nil
+}
+
+// normalizeBankCode removes spaces and dashes and upper-cases s.
+func normalizeBankCode(s string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(s))
+}
+
+// ValidateIBAN checks the structure, country, length and mod-97 check
+// digits of an IBAN. Spaces are ignored.
+func ValidateIBAN(iban string) error {
+	s := normalizeBankCode(iban)
+	match := ibanPattern.FindStringSubmatch(s)
+	if match == nil {
+		return fmt.Errorf("invalid IBAN %q", iban)
+	}
+	length, ok := ibanLengths[match[1]]
+	if !ok {
+		return fmt.Errorf("invalid IBAN %q: %s does not use IBANs", iban, match[1])
+	}
+	if len(s) != length {
+		return fmt.Errorf("invalid IBAN %q: %s IBANs have %d characters", iban, match[1], length)
+	}
+	// Move the country and check digits to the end and read the letters as
+	// numbers from 10 to 35, the result must be 1 modulo 97.
+	rem := 0
+	for _, r := range s[4:] + s[:4] {
+		if r >= 'A' {
+			rem = (rem*100 + int(r-'A'+10)) % 97
+		} else {
+			rem = (rem*10 + int(r-'0')) % 97
+		}
+	}
+	if rem != 1 {
+		return fmt.Errorf("invalid IBAN %q: wrong check digits", iban)
+	}
+	return nil
+}
+
+// ValidateABARoutingNumber checks the length and checksum of a US ABA routing
+// number.
+func ValidateABARoutingNumber(routing string) error {
+	d, ok := digits(routing, 9)
+	if !ok {
+		return fmt.Errorf("invalid routing number %q: must be 9 digits", routing)
+	}
+	sum := 3*(d[0]+d[3]+d[6]) + 7*(d[1]+d[4]+d[7]) + d[2] + d[5] + d[8]
+	if sum%10 != 0 {
+		return fmt.Errorf("invalid routing number %q: wrong check digit", routing)
+	}
+	return nil
+}
+
+// ValidateCLABE checks the length and check digit of a Mexican CLABE.
+func ValidateCLABE(clabe string) error {
+	d, ok := digits(clabe, 18)
+	if !ok {
+		return fmt.Errorf("invalid CLABE %q: must be 18 digits", clabe)
+	}
+	weights := [3]int{3, 7, 1}
+	sum := 0
+	for i, n := range d[:17] {
+		sum += n * weights[i%3] % 10
+	}
+	if (10-sum%10)%10 != d[17] {
+		return fmt.Errorf("invalid CLABE %q: wrong check digit", clabe)
+	}
+	return nil
+}
+
+// ValidateIFSC checks the structure of an Indian IFSC: a four letter bank
+// code, a zero and a six character branch code.
+func ValidateIFSC(ifsc string) error {
+	if !ifscPattern.MatchString(normalizeBankCode(ifsc)) {
+		return fmt.Errorf("invalid IFSC %q", ifsc)
+	}
+	return nil
+}
+
+// ValidateSWIFTBIC checks the structure of a SWIFT/BIC code: a four letter
+// bank code, a country code, a two character location code and an optional
+// three character branch code.
+func ValidateSWIFTBIC(bic string) error {
+	match := swiftPattern.FindStringSubmatch(normalizeBankCode(bic))
+	if match == nil {
+		return fmt.Errorf("invalid SWIFT/BIC %q", bic)
+	}
+	if !CountryCode(match[1]).Valid() {
+		return fmt.Errorf("invalid SWIFT/BIC %q: unknown country %s", bic, match[1])
+	}
+	return nil
+}
+
+// digits returns the digits of s, ignoring spaces and dashes, if it has
+// exactly n of them and nothing else.
+func digits(s string, n int) ([]int, bool) {
+	s = normalizeBankCode(s)
+	if len(s) != n {
+		return nil, false
+	}
+	out := make([]int, n)
+	for i, r := range s {
+		if r < '0' || r > '9' {
+			return nil, false
+		}
+		out[i] = int(r - '0')
+	}
+	return out, true
+}
@@ -0,0 +1,96 @@
+package veem
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBankCodeValidators(t *testing.T) {
+	tests := []struct {
+		name     string
+		validate func(string) error
+		in       string
+		wantErr  bool
+	}{
+		{"iban", ValidateIBAN, "GB82WEST12345698765432", false},
+		{"iban spaced", ValidateIBAN, "gb82 west 1234 5698 7654 32", false},
+		{"iban", ValidateIBAN, "DE89370400440532013000", false},
+		{"iban", ValidateIBAN, "NO9386011117947", false},
+		{"iban", ValidateIBAN, "FR7630006000011234567890189", false},
+		{"iban check digits", ValidateIBAN, "GB82WEST12345698765433", true},
+		{"iban too short", ValidateIBAN, "GB82WEST1234569876543", true},
+		{"iban too long", ValidateIBAN, "DE893704004405320130001", true},
+		{"iban no IBANs", ValidateIBAN, "US64SVBKUS6S3300958879", true},
+		{"iban structure", ValidateIBAN, "GB82", true},
+		{"aba", ValidateABARoutingNumber, "021000021", false},
+		{"aba check digit", ValidateABARoutingNumber, "021000022", true},
+		{"aba length", ValidateABARoutingNumber, "02100002", true},
+		{"aba letters", ValidateABARoutingNumber, "02100002X", true},
+		{"clabe", ValidateCLABE, "032180000118359719", false},
+		{"clabe check digit", ValidateCLABE, "032180000118359718", true},
+		{"clabe length", ValidateCLABE, "03218000011835971", true},
+		{"ifsc", ValidateIFSC, "SBIN0000058", false},
+		{"ifsc lower case", ValidateIFSC, "sbin0000058", false},
+		{"ifsc fifth character", ValidateIFSC, "SBIN1000058", true},
+		{"ifsc length", ValidateIFSC, "SBIN000005", true},
+		{"bic", ValidateSWIFTBIC, "DEUTDEFF", false},
+		{"bic branch", ValidateSWIFTBIC, "DEUTDEFF500", false},
+		{"bic structure", ValidateSWIFTBIC, "DEUT1EFF", true},
+		{"bic length", ValidateSWIFTBIC, "DEUTDEFF5", true},
+		{"bic country", ValidateSWIFTBIC, "DEUTXXFF", true},
+	}
+	for _, tt := range tests {
+		if err := tt.validate(tt.in); (err != nil) != tt.wantErr {
+			t.Errorf("%s %q: error = %v, want error %t", tt.name, tt.in, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateBankAccount(t *testing.T) {
+	meta := NewMetadata([]*CountryCurrentMap{
+		{Country: "GB", BankFields: []string{"iban", "swiftBic"}, ReceivingCurrencies: []CurrencyCode{"GBP"}},
+		{Country: "JE", BankFields: []string{"iban", "swiftBic"}, ReceivingCurrencies: []CurrencyCode{"GBP"}},
+		{Country: "GP", BankFields: []string{"iban", "swiftBic"}, ReceivingCurrencies: []CurrencyCode{"EUR"}},
+		{Country: "US", BankFields: []string{"bankAccountNumber", "routingNumber"}, ReceivingCurrencies: []CurrencyCode{"USD"}},
+		{Country: "AU", BankFields: []string{"accountNumber", "BSB"}, ReceivingCurrencies: []CurrencyCode{"AUD"}},
+		{Country: "CA", BankFields: []string{"bankAccountNumber", "payeeReference"}, ReceivingCurrencies: []CurrencyCode{"CAD"}},
+	}, time.Now())
+
+	tests := []struct {
+		name    string
+		account *BankAccount
+		want    []string
+	}{
+		{"nil", nil, []string{"bankAccount:required"}},
+		{"valid", &BankAccount{ISOCountryCode: "GB", CurrencyCode: "GBP", IBAN: "GB82WEST12345698765432", SwiftBIC: "WESTGB2L"}, nil},
+		{"lower case codes", &BankAccount{ISOCountryCode: "us", CurrencyCode: "usd", AccountNumber: "123", RoutingNumber: "021000021"}, nil},
+		{"jersey uses GB codes", &BankAccount{ISOCountryCode: "JE", CurrencyCode: "GBP", IBAN: "GB82WEST12345698765432", SwiftBIC: "WESTGB2L"}, nil},
+		{"guadeloupe uses FR codes", &BankAccount{ISOCountryCode: "GP", CurrencyCode: "EUR", IBAN: "FR7630006000011234567890189", SwiftBIC: "BNPAFRPP"}, nil},
+		{"iban of another country", &BankAccount{ISOCountryCode: "GB", CurrencyCode: "GBP", IBAN: "DE89370400440532013000", SwiftBIC: "WESTGB2L"}, []string{"iban:invalid"}},
+		{"missing and extraneous", &BankAccount{ISOCountryCode: "US", CurrencyCode: "USD", AccountNumber: "123", IBAN: "GB82WEST12345698765432"},
+			[]string{"iban:extraneous", "iban:invalid", "routingNumber:required"}},
+		{"bad routing number", &BankAccount{ISOCountryCode: "US", CurrencyCode: "USD", AccountNumber: "123", RoutingNumber: "021000022"},
+			[]string{"routingNumber:invalid"}},
+		{"unsupported currency", &BankAccount{ISOCountryCode: "US", CurrencyCode: "EUR", AccountNumber: "123", RoutingNumber: "021000021"},
+			[]string{"currencyCode:unsupported"}},
+		{"aliases", &BankAccount{ISOCountryCode: "AU", CurrencyCode: "AUD", AccountNumber: "123", BSBBankCode: "062000"}, nil},
+		{"alias missing", &BankAccount{ISOCountryCode: "AU", CurrencyCode: "AUD", AccountNumber: "123"}, []string{"bsbBankCode:required"}},
+		{"unknown field", &BankAccount{ISOCountryCode: "CA", CurrencyCode: "CAD", AccountNumber: "123", TransitCode: "00012"},
+			[]string{"payeeReference:unknown"}},
+		{"missing codes", &BankAccount{}, []string{"currencyCode:required", "isoCountryCode:required"}},
+		{"unsupported country", &BankAccount{ISOCountryCode: "FR", CurrencyCode: "EUR"}, []string{"isoCountryCode:unsupported"}},
+		{"unknown codes", &BankAccount{ISOCountryCode: "ZZ", CurrencyCode: "ABC"}, []string{"currencyCode:invalid", "isoCountryCode:invalid"}},
+	}
+	for _, tt := range tests {
+		got := make([]string, 0)
+		for _, p := range meta.ValidateBankAccount(tt.account) {
+			got = append(got, p.Field+":"+p.Code)
+		}
+		sort.Strings(got)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: problems = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -1,12 +1,26 @@
 package veem
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 )
 
 type Client interface {
+	// Authenticate checks the credentials by requesting a new access token
+	// from Veem, ignoring any cached or stored token. The new token is used
+	// for later requests. Failures, including a cancelled context, are
+	// returned as an *AuthError.
+	Authenticate(ctx context.Context) error
+	// AccessToken returns the access token the client is currently using,
+	// refreshing it first if it has expired.
+	AccessToken(ctx context.Context) (*AccessTokenResponse, error)
 	Meta() MetaController
+	// Metadata returns the client's cache of the country-currency map.
+	Metadata() *MetadataCache
 	Attachments() AttachmentController
 	Contacts() ContactController
 	Customers() CustomerController
@@ -18,35 +32,156 @@ type Client interface {
 var sandboxURL = mustParseURL("https://sandbox-api.veem.com")
 var liveURL = mustParseURL("https://api.veem.com")
 
+// DefaultUserAgent is the User-Agent sent with requests when none is
+// configured in the ClientOptions.
+const DefaultUserAgent = "go-veem"
+
 type ClientOptions struct {
 	// Use the sandbox API.
 	UseSandbox bool
 	// ClientID and ClientSecret for authenticating with Veem
 	ClientID, ClientSecret string
+	// Scopes requested for client credentials tokens and by AuthCodeURL.
+	// Defaults to "all".
+	Scopes []string
+	// RedirectURL is the URL Veem redirects users to after they authorize a
+	// partner app, see AuthCodeURL.
+	RedirectURL string
+	// UserToken, if set, makes the client act on behalf of the user the token
+	// was issued to, typically by Exchange. It is refreshed with its refresh
+	// token when it expires instead of using client credentials.
+	UserToken *AccessTokenResponse
+	// BaseURL overrides the API endpoint, e.g. for proxies or local fakes.
+	// When set, UseSandbox is ignored.
+	BaseURL string
+	// HTTPClient is the client used to make requests. Defaults to a new
+	// http.Client. The client is copied and never modified.
+	HTTPClient *http.Client
+	// Transport, when set, replaces the transport of the HTTPClient.
+	Transport http.RoundTripper
+	// Timeout is the time limit for each request made to the API. Zero
+	// means the timeout of the HTTPClient is used.
+	Timeout time.Duration
+	// UserAgent is sent with every request. Defaults to DefaultUserAgent.
+	UserAgent string
+	// RetryPolicy controls retries of failed requests. Nil disables retries,
+	// see DefaultRetryPolicy.
+	RetryPolicy *RetryPolicy
+	// IdempotencyLedger, if set, refuses to re-send Creates whose request ID
+	// (see WithRequestID) already succeeded.
+	IdempotencyLedger IdempotencyLedger
+	// TokenStore, if set, is consulted for an access token before requesting
+	// a new one, and new tokens are written back to it. Tokens are keyed by
+	// user, so a UserToken used with a TokenStore must have a UserID.
+	TokenStore TokenStore
+	// LazyAuth defers retrieving an access token until the first request
+	// instead of doing so in New. See Client.Authenticate.
+	LazyAuth bool
+	// MetadataTTL is how long the country-currency map is cached before it
+	// is refreshed. Defaults to DefaultMetadataTTL.
+	MetadataTTL time.Duration
 }
 
-func New(opts *ClientOptions) (Client, error) {
-	url := liveURL
-	if opts.UseSandbox {
-		url = sandboxURL
+func (o *ClientOptions) apiURL() (*url.URL, error) {
+	if o.BaseURL != "" {
+		return url.Parse(strings.TrimSuffix(o.BaseURL, "/"))
+	}
+	if o.UseSandbox {
+		return sandboxURL, nil
+	}
+	return liveURL, nil
+}
+
+func (o *ClientOptions) scope() string {
+	if len(o.Scopes) == 0 {
+		return "all"
+	}
+	return strings.Join(o.Scopes, " ")
+}
+
+func (o *ClientOptions) httpClient() *http.Client {
+	hc := &http.Client{}
+	if o.HTTPClient != nil {
+		*hc = *o.HTTPClient
 	}
-	c := &client{opts: opts, apiURL: url, client: &http.Client{}}
-	var err error
-	c.token, err = c.getAccessToken()
+	if o.Transport != nil {
+		hc.Transport = o.Transport
+	}
+	if o.Timeout != 0 {
+		hc.Timeout = o.Timeout
+	}
+	return hc
+}
+
+func (o *ClientOptions) userAgent() string {
+	if o.UserAgent != "" {
+		return o.UserAgent
+	}
+	return DefaultUserAgent
+}
+
+// New returns a new Client for the given options. Unless LazyAuth is set, the
+// initial access token is retrieved with a background context, use
+// NewWithContext to bound it.
+func New(opts *ClientOptions) (Client, error) {
+	return NewWithContext(context.Background(), opts)
+}
+
+// NewWithContext is like New but uses the given context when retrieving
+// the initial access token.
+func NewWithContext(ctx context.Context, opts *ClientOptions) (Client, error) {
+	url, err := opts.apiURL()
 	if err != nil {
 		return nil, err
 	}
+	if opts.TokenStore != nil && opts.UserToken != nil && opts.UserToken.UserID == "" {
+		// Every user would share one key in the store.
+		return nil, errors.New("a UserToken without a UserID cannot be used with a TokenStore")
+	}
+	c := &client{opts: opts, apiURL: url, client: opts.httpClient()}
+	c.tokens = newTokenSource(c.fetchToken)
+	c.metadata = newMetadataCache(c.Meta(), opts.MetadataTTL)
+	if opts.UserToken != nil {
+		tok := *opts.UserToken
+		c.tokens.set(&tok)
+	}
+	if opts.LazyAuth {
+		return c, nil
+	}
+	if _, err := c.tokens.Token(ctx); err != nil {
+		return nil, err
+	}
 	return c, nil
 }
 
 type client struct {
-	opts   *ClientOptions
-	apiURL *url.URL
-	client *http.Client
-	token  *AccessTokenResponse
+	opts     *ClientOptions
+	apiURL   *url.URL
+	client   *http.Client
+	tokens   *tokenSource
+	metadata *MetadataCache
+}
+
+func (c *client) Authenticate(ctx context.Context) error {
+	tok, err := c.loadOrGetToken(ctx, true)
+	if err != nil {
+		return &AuthError{Err: err}
+	}
+	c.tokens.set(tok)
+	return nil
+}
+
+func (c *client) AccessToken(ctx context.Context) (*AccessTokenResponse, error) {
+	tok, err := c.tokens.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := *tok
+	return &out, nil
 }
 
 func (c *client) Meta() MetaController                  { return &metaController{c} }
+func (c *client) Metadata() *MetadataCache              { return c.metadata }
 func (c *client) Attachments() AttachmentController     { return &attachmentController{c} }
 func (c *client) Contacts() ContactController           { return &contactController{c} }
 func (c *client) Customers() CustomerController         { return &customerController{c} }
@@ -0,0 +1,67 @@
+package veem
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAuthenticate(t *testing.T) {
+	var calls int32
+	var reject int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if atomic.LoadInt32(&reject) == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		w.Write([]byte(`{"access_token":"a","token_type":"bearer","expires_in":3600}`))
+	}))
+	defer srv.Close()
+	c, err := New(&ClientOptions{BaseURL: srv.URL, TokenStore: NewMemoryTokenStore()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Authenticate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("token requests = %d, want 2: Authenticate must not reuse the cached token", calls)
+	}
+
+	atomic.StoreInt32(&reject, 1)
+	var authErr *AuthError
+	err = c.Authenticate(context.Background())
+	if !errors.As(err, &authErr) || !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Authenticate with revoked credentials = %v, want an *AuthError matching ErrUnauthorized", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = c.Authenticate(ctx)
+	if !errors.As(err, &authErr) || !errors.Is(err, context.Canceled) {
+		t.Errorf("Authenticate with a cancelled context = %v, want an *AuthError matching context.Canceled", err)
+	}
+}
+
+func TestLazyAuth(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+	if _, err := New(&ClientOptions{BaseURL: srv.URL}); err == nil {
+		t.Error("New with bad credentials succeeded, want an error")
+	}
+	c, err := New(&ClientOptions{BaseURL: srv.URL, LazyAuth: true})
+	if err != nil {
+		t.Fatalf("New with LazyAuth = %v, want no error", err)
+	}
+	var authErr *AuthError
+	if err := c.Authenticate(context.Background()); !errors.As(err, &authErr) {
+		t.Errorf("Authenticate = %v, want an *AuthError", err)
+	}
+}
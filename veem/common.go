@@ -5,27 +5,31 @@ type BatchOperation struct {
 	BatchItems     []*BatchItem `json:"batchItems,omitempty"`
 	HasErrors      bool         `json:"hasErrors"`
 	ProcessedItems int64        `json:"processedItems"`
-	Status         string       `json:"status"`
+	Status         BatchStatus  `json:"status"`
 	TotalItems     int64        `json:"totalItems"`
 }
 
 type BatchItem struct {
-	BatchItemID int64     `json:"batchItemId"`
-	ErrorInfo   *APIError `json:"errorInfo,omitempty"`
-	Status      string    `json:"status"`
+	BatchItemID int64       `json:"batchItemId"`
+	ErrorInfo   *APIError   `json:"errorInfo,omitempty"`
+	Status      BatchStatus `json:"status"`
+}
+
+// ValidationError returns the field errors of a failed item, or nil if the
+// item did not fail validation.
+func (b *BatchItem) ValidationError() *ValidationError {
+	if b.ErrorInfo == nil || len(b.ErrorInfo.FieldErrors) == 0 {
+		return nil
+	}
+	return &ValidationError{APIError: b.ErrorInfo}
 }
 
 type Entity struct {
 	BusinessName string      `json:"businessName,omitempty"`
-	CountryCode  string      `json:"countryCode"`
+	CountryCode  CountryCode `json:"countryCode"`
 	Email        string      `json:"email"`
 	FirstName    string      `json:"firstName"`
 	LastName     string      `json:"lastName"`
 	Type         ContactType `json:"type"`
 	Phone        string      `json:"phone"`
 }
-
-type Amount struct {
-	Currency string  `json:"currency"`
-	Number   float64 `json:"number"`
-}
@@ -2,25 +2,44 @@ package veem
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
-	"net/url"
+	"strings"
 )
 
 // ContactController is the interface for interacting with Veem contacts.
 type ContactController interface {
 	// Get an account contact by ID
-	Get(id int64) (*Contact, error)
+	Get(ctx context.Context, id int64) (*Contact, error)
 	// Get a page of account contacts by email address, first,last name, batchId, and business name
-	List(filters ...Filter) (*ListContactsResponse, error)
+	List(ctx context.Context, filters ...ContactFilter) (*ListContactsResponse, error)
+	// Iterate over every account contact matching the filters
+	All(ctx context.Context, filters ...ContactFilter) *ContactIterator
 	// Create a contact
-	Create(contact *ContactFull) (*Contact, error)
+	Create(ctx context.Context, contact *ContactFull) (*Contact, error)
 	// Create a batch of contacts
-	CreateBatch(contacts []*ContactFull, includeItems bool) (*BatchOperation, error)
+	CreateBatch(ctx context.Context, contacts []*ContactFull, includeItems bool) (*BatchOperation, error)
 	// Get the status of a batch operation
-	GetBatch(batchID int64, includeItems bool) (*BatchOperation, error)
+	GetBatch(ctx context.Context, batchID int64, includeItems bool) (*BatchOperation, error)
+	// Replace every field of a contact
+	Update(ctx context.Context, id int64, contact *ContactFull) (*Contact, error)
+	// Change only the fields set in the patch
+	Patch(ctx context.Context, id int64, patch *ContactPatch) (*Contact, error)
+	// Replace the bank account of a contact
+	ReplaceBankAccount(ctx context.Context, id int64, account *BankAccount) (*Contact, error)
+	// Delete a contact
+	Delete(ctx context.Context, id int64) error
+	// Find the single contact with an email address
+	FindByEmail(ctx context.Context, email string) (*Contact, error)
+	// Create a contact, or update the existing one if it has changed
+	Upsert(ctx context.Context, contact *ContactFull) (*Contact, UpsertAction, error)
+	// Upsert many contacts against a single listing of the account's contacts
+	UpsertAll(ctx context.Context, contacts []*ContactFull) ([]*UpsertResult, error)
+	// Find likely duplicate contacts in the account
+	Dedupe(ctx context.Context) ([]*DuplicateContacts, error)
 }
 
 type ContactType string
@@ -32,16 +51,19 @@ const (
 )
 
 type Contact struct {
-	ID               int64  `json:"id,omitempty"`
-	BusinessName     string `json:"businessName,omitempty"`
-	FirstName        string `json:"firstName"`
-	LastName         string `json:"lastName"`
-	Email            string `json:"email"`
-	ISOCountryCode   string `json:"isoCountryCode"`
-	PhoneDialCode    string `json:"dialCode"`
-	PhoneNumber      string `json:"phoneNumber"`
-	BatchItemID      int64  `json:"batchItemId"`
-	ContactAccountID int64  `json:"contactAccountId"`
+	ID               int64       `json:"id,omitempty"`
+	BusinessName     string      `json:"businessName,omitempty"`
+	FirstName        string      `json:"firstName"`
+	LastName         string      `json:"lastName"`
+	Email            string      `json:"email"`
+	ISOCountryCode   CountryCode `json:"isoCountryCode"`
+	PhoneDialCode    string      `json:"dialCode"`
+	PhoneNumber      string      `json:"phoneNumber"`
+	BatchItemID      int64       `json:"batchItemId"`
+	ContactAccountID int64       `json:"contactAccountId"`
+	// ExternalBusinessID is returned for contacts created with one, see
+	// ContactFull.ExternalBusinessID.
+	ExternalBusinessID int64 `json:"externalBusinessID,omitempty"`
 }
 
 func (c *Contact) ToEntity(t ContactType) *Entity {
@@ -106,6 +128,42 @@ func (c *ContactFull) MarshalJSON() ([]byte, error) {
 	return json.Marshal(out)
 }
 
+// ContactPatch holds the fields to change in a partial update. Nil fields are
+// left unchanged.
+type ContactPatch struct {
+	BusinessName    *string      `json:"businessName,omitempty"`
+	FirstName       *string      `json:"firstName,omitempty"`
+	LastName        *string      `json:"lastName,omitempty"`
+	Email           *string      `json:"email,omitempty"`
+	ISOCountryCode  *CountryCode `json:"isoCountryCode,omitempty"`
+	PhoneDialCode   *string      `json:"phoneDialCode,omitempty"`
+	PhoneNumber     *string      `json:"phoneNumber,omitempty"`
+	BusinessAddress *Address     `json:"businessAddress,omitempty"`
+	BankAccount     *BankAccount `json:"bankAccount,omitempty"`
+}
+
+// AmbiguousContactError is returned by FindByEmail and Upsert when more than
+// one contact matches.
+type AmbiguousContactError struct {
+	Email string
+	// The external business ID or business name the contacts were matched
+	// by, if not the email.
+	ExternalBusinessID int64
+	BusinessName       string
+	Contacts           []*Contact
+}
+
+// Error implements the error interface.
+func (a *AmbiguousContactError) Error() string {
+	switch {
+	case a.ExternalBusinessID != 0:
+		return fmt.Sprintf("%d contacts have the external business ID %d", len(a.Contacts), a.ExternalBusinessID)
+	case a.Email == "" && a.BusinessName != "":
+		return fmt.Sprintf("%d contacts have the business name %s", len(a.Contacts), a.BusinessName)
+	}
+	return fmt.Sprintf("%d contacts have the email %s", len(a.Contacts), a.Email)
+}
+
 type Address struct {
 	Line1         string `json:"line1"`
 	Line2         string `json:"line2"`
@@ -115,46 +173,34 @@ type Address struct {
 }
 
 type BankAccount struct {
-	AccountNumber         string   `json:"bankAccountNumber,omitempty"`
-	RoutingNumber         string   `json:"routingNumber,omitempty"`
-	BankName              string   `json:"bankName,omitempty"`
-	BankAddress           *Address `json:"bankAddress,omitempty"`
-	BankCNaps             string   `json:"bankCnaps,omitempty"`
-	BankCode              string   `json:"bankCode,omitempty"`
-	BankIFSCBranchCode    string   `json:"bankIfscBranchCode,omitempty"`
-	BankInstitutionNumber string   `json:"bankInstitutionNumber,omitempty"`
-	BeneficiaryName       string   `json:"beneficiaryName,omitempty"`
-	BranchCode            string   `json:"branchCode,omitempty"`
-	BSBBankCode           string   `json:"bsbBankCode,omitempty"`
-	CLABE                 string   `json:"clabe,omitempty"`
-	CurrencyCode          string   `json:"currencyCode,omitempty"`
-	IBAN                  string   `json:"iban,omitempty"`
-	ISOCountryCode        string   `json:"isoCountryCode,omitempty"`
-	SortCode              string   `json:"sortCode,omitempty"`
-	SwiftBIC              string   `json:"swiftBic,omitempty"`
-	TransitCode           string   `json:"transitCode,omitempty"`
+	AccountNumber         string       `json:"bankAccountNumber,omitempty"`
+	RoutingNumber         string       `json:"routingNumber,omitempty"`
+	BankName              string       `json:"bankName,omitempty"`
+	BankAddress           *Address     `json:"bankAddress,omitempty"`
+	BankCNaps             string       `json:"bankCnaps,omitempty"`
+	BankCode              string       `json:"bankCode,omitempty"`
+	BankIFSCBranchCode    string       `json:"bankIfscBranchCode,omitempty"`
+	BankInstitutionNumber string       `json:"bankInstitutionNumber,omitempty"`
+	BeneficiaryName       string       `json:"beneficiaryName,omitempty"`
+	BranchCode            string       `json:"branchCode,omitempty"`
+	BSBBankCode           string       `json:"bsbBankCode,omitempty"`
+	CLABE                 string       `json:"clabe,omitempty"`
+	CurrencyCode          CurrencyCode `json:"currencyCode,omitempty"`
+	IBAN                  string       `json:"iban,omitempty"`
+	ISOCountryCode        CountryCode  `json:"isoCountryCode,omitempty"`
+	SortCode              string       `json:"sortCode,omitempty"`
+	SwiftBIC              string       `json:"swiftBic,omitempty"`
+	TransitCode           string       `json:"transitCode,omitempty"`
 }
 
 type contactController struct{ *client }
 
-type ListContactsResponse struct {
-	Contacts []*Contact `json:"content"`
+// ListContactsResponse is a page of contacts.
+type ListContactsResponse = Page[*Contact]
 
-	First            bool  `json:"first"`
-	Last             bool  `json:"last"`
-	NumberOfElements int   `json:"numberOfElements"`
-	TotalElements    int   `json:"totalElements"`
-	PageNumber       int32 `json:"number"`
-	PageSize         int32 `json:"size"`
-	TotalPages       int   `json:"totalPages"`
-
-	controller *contactController
-	filters    []Filter
-}
-
-func (c *contactController) Get(id int64) (*Contact, error) {
+func (c *contactController) Get(ctx context.Context, id int64) (*Contact, error) {
 	ep := fmt.Sprintf("veem/v1.1/contacts/%d", id)
-	req, err := c.newRequest(http.MethodGet, ep, nil)
+	req, err := c.newRequest(ctx, http.MethodGet, ep, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -162,40 +208,58 @@ func (c *contactController) Get(id int64) (*Contact, error) {
 	return contact, c.doIntoWithAuth(req, contact)
 }
 
-func (c *contactController) List(filters ...Filter) (*ListContactsResponse, error) {
-	ep := "veem/v1.1/contacts"
-	if len(filters) > 0 {
-		vals := &url.Values{}
-		for _, f := range filters {
-			f(vals)
-		}
-		ep = fmt.Sprintf("%s?%s", ep, vals.Encode())
-	} else {
-		filters = make([]Filter, 0)
+func (c *contactController) List(ctx context.Context, filters ...ContactFilter) (*ListContactsResponse, error) {
+	return listPage[*Contact](ctx, c.client, "veem/v1.1/contacts", toFilters(filters))
+}
+
+func (c *contactController) All(ctx context.Context, filters ...ContactFilter) *ContactIterator {
+	return &ContactIterator{ctx: ctx, first: func() (*ListContactsResponse, error) { return c.List(ctx, filters...) }}
+}
+
+func (c *contactController) Create(ctx context.Context, contact *ContactFull) (*Contact, error) {
+	payload, err := marshalRequest(contact)
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.newCreateRequest(ctx, "veem/v1.1/contacts", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	out := &Contact{}
+	return out, c.doIntoWithAuth(req, out)
+}
+
+func (c *contactController) CreateBatch(ctx context.Context, contacts []*ContactFull, includeItems bool) (*BatchOperation, error) {
+	payload, err := marshalRequest(contacts)
+	if err != nil {
+		return nil, err
 	}
-	req, err := c.newRequest(http.MethodGet, ep, nil)
+	ep := fmt.Sprintf("veem/v1.1/contacts/batch?includeItems=%t", includeItems)
+	req, err := c.newCreateRequest(ctx, ep, bytes.NewReader(payload))
 	if err != nil {
 		return nil, err
 	}
-	out := &ListContactsResponse{controller: c, filters: filters}
+	out := &BatchOperation{}
 	return out, c.doIntoWithAuth(req, out)
 }
 
-func (g *ListContactsResponse) Next() (*ListContactsResponse, error) {
-	if g.Last {
-		return nil, errors.New("no more contact pages left")
+func (c *contactController) GetBatch(ctx context.Context, batchID int64, includeItems bool) (*BatchOperation, error) {
+	ep := fmt.Sprintf("veem/v1.1/contacts/batch/%d?includeItems=%t", batchID, includeItems)
+	req, err := c.newRequest(ctx, http.MethodGet, ep, nil)
+	if err != nil {
+		return nil, err
 	}
-	return g.controller.List(
-		append(g.filters, WithPageNumber(g.PageNumber+1), WithPageSize(g.PageSize))...,
-	)
+	out := &BatchOperation{}
+	return out, c.doIntoWithAuth(req, out)
 }
 
-func (c *contactController) Create(contact *ContactFull) (*Contact, error) {
-	payload, err := json.Marshal(contact)
+func (c *contactController) Update(ctx context.Context, id int64, contact *ContactFull) (*Contact, error) {
+	payload, err := marshalRequest(contact)
 	if err != nil {
 		return nil, err
 	}
-	req, err := c.newRequest(http.MethodPost, "veem/v1.1/contacts", bytes.NewReader(payload))
+	ep := fmt.Sprintf("veem/v1.1/contacts/%d", id)
+	req, err := c.newRequest(ctx, http.MethodPut, ep, bytes.NewReader(payload))
 	if err != nil {
 		return nil, err
 	}
@@ -203,26 +267,59 @@ func (c *contactController) Create(contact *ContactFull) (*Contact, error) {
 	return out, c.doIntoWithAuth(req, out)
 }
 
-func (c *contactController) CreateBatch(contacts []*ContactFull, includeItems bool) (*BatchOperation, error) {
-	payload, err := json.Marshal(contacts)
+func (c *contactController) Patch(ctx context.Context, id int64, patch *ContactPatch) (*Contact, error) {
+	payload, err := marshalRequest(patch)
 	if err != nil {
 		return nil, err
 	}
-	ep := fmt.Sprintf("veem/v1.1/contacts/batch?includeItems=%t", includeItems)
-	req, err := c.newRequest(http.MethodPost, ep, bytes.NewReader(payload))
+	ep := fmt.Sprintf("veem/v1.1/contacts/%d", id)
+	req, err := c.newRequest(ctx, http.MethodPatch, ep, bytes.NewReader(payload))
 	if err != nil {
 		return nil, err
 	}
-	out := &BatchOperation{}
+	out := &Contact{}
 	return out, c.doIntoWithAuth(req, out)
 }
 
-func (c *contactController) GetBatch(batchID int64, includeItems bool) (*BatchOperation, error) {
-	ep := fmt.Sprintf("veem/v1.1/contacts/batch/%d?includeItems=%t", batchID, includeItems)
-	req, err := c.newRequest(http.MethodGet, ep, nil)
+func (c *contactController) ReplaceBankAccount(ctx context.Context, id int64, account *BankAccount) (*Contact, error) {
+	if account == nil {
+		return nil, errors.New("a bank account is required")
+	}
+	return c.Patch(ctx, id, &ContactPatch{BankAccount: account})
+}
+
+func (c *contactController) Delete(ctx context.Context, id int64) error {
+	ep := fmt.Sprintf("veem/v1.1/contacts/%d", id)
+	req, err := c.newRequest(ctx, http.MethodDelete, ep, nil)
 	if err != nil {
+		return err
+	}
+	res, err := c.doWithAuth(req, "application/json")
+	if err != nil {
+		return err
+	}
+	return res.Close()
+}
+
+// FindByEmail returns the contact with exactly the given email address,
+// ignoring case. It returns an error matching ErrNotFound if there is none,
+// and an *AmbiguousContactError if there are several.
+func (c *contactController) FindByEmail(ctx context.Context, email string) (*Contact, error) {
+	matches := make([]*Contact, 0)
+	it := c.All(ctx, WithEmail(email))
+	for it.Next() {
+		if contact := it.Item(); strings.EqualFold(contact.Email, email) {
+			matches = append(matches, contact)
+		}
+	}
+	if err := it.Err(); err != nil {
 		return nil, err
 	}
-	out := &BatchOperation{}
-	return out, c.doIntoWithAuth(req, out)
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no contact with email %s: %w", email, ErrNotFound)
+	case 1:
+		return matches[0], nil
+	}
+	return nil, &AmbiguousContactError{Email: email, Contacts: matches}
 }
@@ -0,0 +1,446 @@
+package veem
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// UpsertAction is what Upsert did with a contact.
+type UpsertAction string
+
+const (
+	UpsertCreated   UpsertAction = "Created"
+	UpsertUpdated   UpsertAction = "Updated"
+	UpsertUnchanged UpsertAction = "Unchanged"
+)
+
+// Upsert finds the existing contact matching the given one and updates the
+// fields that differ, or creates the contact if there is none. Contacts with
+// the same email, or the same business name when there is no email, are
+// looked up first. If ExternalBusinessID is set and none of them has it,
+// every contact is searched for it as a last resort, so a contact whose email
+// has changed is still found. Contacts with a different external business ID
+// never match. It returns an *AmbiguousContactError if several contacts
+// match. Use UpsertAll to upsert many contacts, which lists the account's
+// contacts only once.
+//
+// The bank account is only sent when creating a contact, as the API does not
+// return it to compare. Use ReplaceBankAccount to change it.
+func (c *contactController) Upsert(ctx context.Context, contact *ContactFull) (*Contact, UpsertAction, error) {
+	if contact == nil || contact.Contact == nil {
+		return nil, "", errors.New("a contact is required")
+	}
+	index, err := c.candidates(ctx, contact)
+	if err != nil {
+		return nil, "", err
+	}
+	return c.upsert(ctx, index, contact)
+}
+
+// UpsertResult is the outcome of upserting one contact with UpsertAll.
+type UpsertResult struct {
+	Contact *Contact
+	Action  UpsertAction
+	Err     error
+}
+
+// UpsertAll upserts each contact like Upsert, matching them against a single
+// listing of the account's contacts rather than searching for each one. The
+// results are in the order of the contacts, and a contact that fails does not
+// stop the others. The error is only set if the contacts could not be listed.
+func (c *contactController) UpsertAll(ctx context.Context, contacts []*ContactFull) ([]*UpsertResult, error) {
+	all, err := collectContacts(c.All(ctx))
+	if err != nil {
+		return nil, err
+	}
+	index := newContactIndex(all)
+	results := make([]*UpsertResult, len(contacts))
+	for i, contact := range contacts {
+		r := &UpsertResult{}
+		if contact == nil || contact.Contact == nil {
+			r.Err = errors.New("a contact is required")
+		} else {
+			r.Contact, r.Action, r.Err = c.upsert(ctx, index, contact)
+		}
+		results[i] = r
+	}
+	return results, nil
+}
+
+// candidates returns an index of the contacts that may match want.
+func (c *contactController) candidates(ctx context.Context, want *ContactFull) (*contactIndex, error) {
+	var filter ContactFilter
+	switch {
+	case want.Email != "":
+		filter = WithEmail(want.Email)
+	case want.BusinessName != "":
+		filter = WithBusinessName(want.BusinessName)
+	default:
+		return nil, errors.New("an email or business name is required to match contacts")
+	}
+	matches, err := collectContacts(c.All(ctx, filter))
+	if err != nil {
+		return nil, err
+	}
+	index := newContactIndex(matches)
+	if want.ExternalBusinessID == 0 || len(index.byExternalID[want.ExternalBusinessID]) > 0 {
+		return index, nil
+	}
+	all, err := collectContacts(c.All(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return newContactIndex(all), nil
+}
+
+// upsert creates or updates the contact matching want in the index, and
+// records the result in the index.
+func (c *contactController) upsert(ctx context.Context, index *contactIndex, want *ContactFull) (*Contact, UpsertAction, error) {
+	existing, err := index.match(want)
+	if err != nil {
+		return nil, "", err
+	}
+	if existing == nil {
+		created, err := c.Create(ctx, want)
+		if err != nil {
+			return nil, "", err
+		}
+		if created.ExternalBusinessID == 0 {
+			created.ExternalBusinessID = want.ExternalBusinessID
+		}
+		index.add(created)
+		return created, UpsertCreated, nil
+	}
+	patch, changed := contactChanges(existing, want)
+	if !changed {
+		return existing, UpsertUnchanged, nil
+	}
+	updated, err := c.Patch(ctx, existing.ID, patch)
+	if err != nil {
+		return nil, "", err
+	}
+	index.replace(existing, updated)
+	return updated, UpsertUpdated, nil
+}
+
+// contactIndex looks up contacts by external business ID, email and
+// business name.
+type contactIndex struct {
+	byExternalID   map[int64][]*Contact
+	byEmail        map[string][]*Contact
+	byBusinessName map[string][]*Contact
+}
+
+func newContactIndex(contacts []*Contact) *contactIndex {
+	index := &contactIndex{
+		byExternalID:   make(map[int64][]*Contact),
+		byEmail:        make(map[string][]*Contact),
+		byBusinessName: make(map[string][]*Contact),
+	}
+	for _, contact := range contacts {
+		index.add(contact)
+	}
+	return index
+}
+
+func (x *contactIndex) add(c *Contact) {
+	if c.ExternalBusinessID != 0 {
+		x.byExternalID[c.ExternalBusinessID] = append(x.byExternalID[c.ExternalBusinessID], c)
+	}
+	if email := strings.ToLower(c.Email); email != "" {
+		x.byEmail[email] = append(x.byEmail[email], c)
+	}
+	if name := normalizeBusinessName(c.BusinessName); name != "" {
+		x.byBusinessName[name] = append(x.byBusinessName[name], c)
+	}
+}
+
+func (x *contactIndex) remove(c *Contact) {
+	if c.ExternalBusinessID != 0 {
+		x.byExternalID[c.ExternalBusinessID] = removeContact(x.byExternalID[c.ExternalBusinessID], c)
+	}
+	if email := strings.ToLower(c.Email); email != "" {
+		x.byEmail[email] = removeContact(x.byEmail[email], c)
+	}
+	if name := normalizeBusinessName(c.BusinessName); name != "" {
+		x.byBusinessName[name] = removeContact(x.byBusinessName[name], c)
+	}
+}
+
+// replace swaps a contact for its updated version, which keeps the external
+// business ID if the API did not return it.
+func (x *contactIndex) replace(old, updated *Contact) {
+	x.remove(old)
+	if updated.ExternalBusinessID == 0 {
+		updated.ExternalBusinessID = old.ExternalBusinessID
+	}
+	x.add(updated)
+}
+
+func removeContact(list []*Contact, c *Contact) []*Contact {
+	out := make([]*Contact, 0, len(list))
+	for _, item := range list {
+		if item != c {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// match returns the contact matching want, or nil if there is none.
+func (x *contactIndex) match(want *ContactFull) (*Contact, error) {
+	if want.ExternalBusinessID != 0 {
+		switch matches := x.byExternalID[want.ExternalBusinessID]; len(matches) {
+		case 0:
+		case 1:
+			return matches[0], nil
+		default:
+			return nil, &AmbiguousContactError{ExternalBusinessID: want.ExternalBusinessID, Contacts: matches}
+		}
+	}
+
+	var candidates []*Contact
+	switch {
+	case want.Email != "":
+		candidates = x.byEmail[strings.ToLower(want.Email)]
+	case want.BusinessName != "":
+		candidates = x.byBusinessName[normalizeBusinessName(want.BusinessName)]
+	default:
+		return nil, errors.New("an email or business name is required to match contacts")
+	}
+	matches := make([]*Contact, 0, len(candidates))
+	for _, cand := range candidates {
+		// A different external ID is a different contact.
+		if want.ExternalBusinessID == 0 || cand.ExternalBusinessID == 0 {
+			matches = append(matches, cand)
+		}
+	}
+	if len(matches) > 1 && want.Email != "" && want.BusinessName != "" {
+		narrowed := make([]*Contact, 0)
+		for _, m := range matches {
+			if normalizeBusinessName(m.BusinessName) == normalizeBusinessName(want.BusinessName) {
+				narrowed = append(narrowed, m)
+			}
+		}
+		if len(narrowed) > 0 {
+			matches = narrowed
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return matches[0], nil
+	}
+	return nil, &AmbiguousContactError{Email: want.Email, BusinessName: want.BusinessName, Contacts: matches}
+}
+
+// collectContacts returns every contact of the iterator.
+func collectContacts(it *ContactIterator) ([]*Contact, error) {
+	out := make([]*Contact, 0)
+	for it.Next() {
+		out = append(out, it.Item())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// contactChanges returns a patch of the fields set in want that differ from
+// have.
+func contactChanges(have *Contact, want *ContactFull) (*ContactPatch, bool) {
+	patch := &ContactPatch{}
+	changed := false
+	diff := func(dst **string, have, want string, fold bool) {
+		if want == "" || have == want || fold && strings.EqualFold(have, want) {
+			return
+		}
+		v := want
+		*dst = &v
+		changed = true
+	}
+	diff(&patch.BusinessName, have.BusinessName, want.BusinessName, false)
+	diff(&patch.FirstName, have.FirstName, want.FirstName, false)
+	diff(&patch.LastName, have.LastName, want.LastName, false)
+	diff(&patch.Email, have.Email, want.Email, true)
+	diff(&patch.PhoneDialCode, have.PhoneDialCode, want.PhoneDialCode, false)
+	diff(&patch.PhoneNumber, have.PhoneNumber, want.PhoneNumber, false)
+	if want.ISOCountryCode != "" && !strings.EqualFold(string(have.ISOCountryCode), string(want.ISOCountryCode)) {
+		country := want.ISOCountryCode
+		patch.ISOCountryCode = &country
+		changed = true
+	}
+	return patch, changed
+}
+
+// DuplicateReason is why contacts are considered duplicates.
+type DuplicateReason string
+
+const (
+	DuplicateEmail              DuplicateReason = "Email"
+	DuplicateExternalBusinessID DuplicateReason = "ExternalBusinessID"
+	DuplicateBusinessName       DuplicateReason = "BusinessName"
+	DuplicateName               DuplicateReason = "Name"
+)
+
+// DuplicateContacts is a group of contacts that are likely the same, with a
+// suggested merge.
+type DuplicateContacts struct {
+	// Why the contacts were grouped.
+	Reasons []DuplicateReason
+	// The contacts, ordered by ID.
+	Contacts []*Contact
+	// The suggested contact to keep: the most complete one, or the oldest if
+	// several are equally complete.
+	Keep *Contact
+	// The contacts to merge into Keep and then delete.
+	Merge []*Contact
+}
+
+// Dedupe lists every contact in the account and groups those sharing an
+// email address, an external business ID, a business name in the same
+// country, or a person's name in the same country. Business names are
+// compared ignoring case, punctuation and legal suffixes such as "Inc".
+func (c *contactController) Dedupe(ctx context.Context) ([]*DuplicateContacts, error) {
+	contacts, err := collectContacts(c.All(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return findDuplicates(contacts), nil
+}
+
+func findDuplicates(contacts []*Contact) []*DuplicateContacts {
+	// Union the contacts sharing any key, remembering why.
+	parent := make([]int, len(contacts))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	first := make(map[duplicateKey]int)
+	reasons := make(map[int]map[DuplicateReason]bool)
+	for i, contact := range contacts {
+		for _, k := range duplicateKeys(contact) {
+			j, ok := first[k]
+			if !ok {
+				first[k] = i
+				continue
+			}
+			a, b := find(i), find(j)
+			parent[a] = b
+			if reasons[b] == nil {
+				reasons[b] = make(map[DuplicateReason]bool)
+			}
+			reasons[b][k.reason] = true
+			for r := range reasons[a] {
+				reasons[b][r] = true
+			}
+		}
+	}
+
+	groups := make(map[int]*DuplicateContacts)
+	roots := make([]int, 0)
+	for i, contact := range contacts {
+		root := find(i)
+		g, ok := groups[root]
+		if !ok {
+			g = &DuplicateContacts{}
+			groups[root] = g
+			roots = append(roots, root)
+		}
+		g.Contacts = append(g.Contacts, contact)
+	}
+	out := make([]*DuplicateContacts, 0)
+	for _, root := range roots {
+		g := groups[root]
+		if len(g.Contacts) < 2 {
+			continue
+		}
+		for r := range reasons[root] {
+			g.Reasons = append(g.Reasons, r)
+		}
+		sort.Slice(g.Reasons, func(i, j int) bool { return g.Reasons[i] < g.Reasons[j] })
+		sort.Slice(g.Contacts, func(i, j int) bool { return g.Contacts[i].ID < g.Contacts[j].ID })
+		g.Keep = g.Contacts[0]
+		for _, contact := range g.Contacts[1:] {
+			if contactCompleteness(contact) > contactCompleteness(g.Keep) {
+				g.Keep = contact
+			}
+		}
+		for _, contact := range g.Contacts {
+			if contact != g.Keep {
+				g.Merge = append(g.Merge, contact)
+			}
+		}
+		out = append(out, g)
+	}
+	return out
+}
+
+// duplicateKey is a value identifying a contact.
+type duplicateKey struct {
+	reason DuplicateReason
+	value  string
+}
+
+// duplicateKeys returns the values identifying the contact.
+func duplicateKeys(c *Contact) []duplicateKey {
+	keys := make([]duplicateKey, 0, 4)
+	country := strings.ToUpper(string(c.ISOCountryCode))
+	if email := strings.ToLower(strings.TrimSpace(c.Email)); email != "" {
+		keys = append(keys, duplicateKey{DuplicateEmail, email})
+	}
+	if c.ExternalBusinessID != 0 {
+		keys = append(keys, duplicateKey{DuplicateExternalBusinessID, strconv.FormatInt(c.ExternalBusinessID, 10)})
+	}
+	if name := normalizeBusinessName(c.BusinessName); name != "" {
+		keys = append(keys, duplicateKey{DuplicateBusinessName, country + " " + name})
+	} else if name := normalizeBusinessName(c.FirstName + " " + c.LastName); name != "" {
+		keys = append(keys, duplicateKey{DuplicateName, country + " " + name})
+	}
+	return keys
+}
+
+// contactCompleteness counts the fields set on the contact.
+func contactCompleteness(c *Contact) int {
+	n := 0
+	for _, v := range []string{c.BusinessName, c.FirstName, c.LastName, c.Email, string(c.ISOCountryCode), c.PhoneNumber} {
+		if v != "" {
+			n++
+		}
+	}
+	if c.ExternalBusinessID != 0 {
+		n++
+	}
+	return n
+}
+
+// legalSuffixes are dropped from the end of business names before comparing
+// them.
+var legalSuffixes = map[string]bool{
+	"inc": true, "incorporated": true, "llc": true, "ltd": true, "limited": true,
+	"corp": true, "corporation": true, "co": true, "company": true, "gmbh": true,
+	"plc": true, "sa": true, "sarl": true, "bv": true, "pty": true, "srl": true,
+}
+
+// normalizeBusinessName lower-cases the name, drops punctuation and legal
+// suffixes, and collapses spaces.
+func normalizeBusinessName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for len(words) > 1 && legalSuffixes[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	}
+	return strings.Join(words, " ")
+}
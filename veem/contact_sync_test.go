@@ -0,0 +1,362 @@
+package veem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// contactServer is a fake contacts API holding contacts in memory.
+type contactServer struct {
+	mu       sync.Mutex
+	contacts []*Contact
+	methods  []string
+}
+
+func (s *contactServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.methods = append(s.methods, r.Method)
+	switch r.Method {
+	case http.MethodGet:
+		matches := make([]*Contact, 0)
+		q := r.URL.Query()
+		for _, c := range s.contacts {
+			if email := q.Get("email"); email != "" && !strings.EqualFold(c.Email, email) {
+				continue
+			}
+			if name := q.Get("businessName"); name != "" && !strings.EqualFold(c.BusinessName, name) {
+				continue
+			}
+			matches = append(matches, c)
+		}
+		json.NewEncoder(w).Encode(&Page[*Contact]{
+			Items: matches, PageSize: int32(len(matches)), TotalPages: 1, TotalElements: len(matches), NumberOfElements: len(matches), Last: true,
+		})
+	case http.MethodPost:
+		var c Contact
+		json.NewDecoder(r.Body).Decode(&c)
+		c.ID = int64(len(s.contacts) + 1)
+		s.contacts = append(s.contacts, &c)
+		json.NewEncoder(w).Encode(&c)
+	case http.MethodPatch:
+		id, _ := strconv.ParseInt(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:], 10, 64)
+		for _, c := range s.contacts {
+			if c.ID == id {
+				json.NewDecoder(r.Body).Decode(c)
+				json.NewEncoder(w).Encode(c)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestUpsert(t *testing.T) {
+	tests := []struct {
+		name       string
+		existing   []*Contact
+		upsert     *Contact
+		externalID int64
+		want       UpsertAction
+		wantEmail  string
+		wantErr    bool
+	}{
+		{
+			name:      "created",
+			upsert:    &Contact{Email: "a@example.com", FirstName: "Ann"},
+			want:      UpsertCreated,
+			wantEmail: "a@example.com",
+		},
+		{
+			name:      "unchanged",
+			existing:  []*Contact{{ID: 1, Email: "a@example.com", FirstName: "Ann"}},
+			upsert:    &Contact{Email: "A@example.com", FirstName: "Ann"},
+			want:      UpsertUnchanged,
+			wantEmail: "a@example.com",
+		},
+		{
+			name:      "updated",
+			existing:  []*Contact{{ID: 1, Email: "a@example.com", FirstName: "Ann"}},
+			upsert:    &Contact{Email: "a@example.com", FirstName: "Anne"},
+			want:      UpsertUpdated,
+			wantEmail: "a@example.com",
+		},
+		{
+			name:       "email changed, matched by external ID",
+			existing:   []*Contact{{ID: 1, Email: "old@example.com", ExternalBusinessID: 7}},
+			upsert:     &Contact{Email: "new@example.com"},
+			externalID: 7,
+			want:       UpsertUpdated,
+			wantEmail:  "new@example.com",
+		},
+		{
+			name:       "different external ID is a different contact",
+			existing:   []*Contact{{ID: 1, Email: "a@example.com", ExternalBusinessID: 8}},
+			upsert:     &Contact{Email: "a@example.com"},
+			externalID: 7,
+			want:       UpsertCreated,
+			wantEmail:  "a@example.com",
+		},
+		{
+			name:       "ambiguous external ID",
+			existing:   []*Contact{{ID: 1, Email: "a@example.com", ExternalBusinessID: 7}, {ID: 2, Email: "b@example.com", ExternalBusinessID: 7}},
+			upsert:     &Contact{Email: "c@example.com"},
+			externalID: 7,
+			wantErr:    true,
+		},
+		{
+			name:     "ambiguous email",
+			existing: []*Contact{{ID: 1, Email: "a@example.com"}, {ID: 2, Email: "a@example.com"}},
+			upsert:   &Contact{Email: "a@example.com"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &contactServer{contacts: tt.existing}
+			c := newTestClient(t, nil, srv.ServeHTTP)
+			got, action, err := c.Contacts().Upsert(context.Background(), &ContactFull{Contact: tt.upsert, ExternalBusinessID: tt.externalID})
+			if tt.wantErr {
+				var ambiguous *AmbiguousContactError
+				if !errors.As(err, &ambiguous) || action != "" || got != nil {
+					t.Fatalf("Upsert = %v, %q, %v, want an *AmbiguousContactError", got, action, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if action != tt.want || got.Email != tt.wantEmail {
+				t.Errorf("Upsert = %s with email %s, want %s with email %s", action, got.Email, tt.want, tt.wantEmail)
+			}
+		})
+	}
+}
+
+func TestUpsertFailure(t *testing.T) {
+	c := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"content":[],"last":true}`))
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message":"bad"}`))
+	})
+	got, action, err := c.Contacts().Upsert(context.Background(), &ContactFull{Contact: &Contact{Email: "a@example.com"}})
+	if err == nil || action != "" || got != nil {
+		t.Errorf("Upsert = %v, %q, %v, want an error and no action", got, action, err)
+	}
+}
+
+func TestContactChanges(t *testing.T) {
+	have := &Contact{BusinessName: "Acme", Email: "a@example.com", ISOCountryCode: "US"}
+	tests := []struct {
+		name   string
+		want   *Contact
+		fields []string
+	}{
+		{"same", &Contact{BusinessName: "Acme", Email: "A@EXAMPLE.COM", ISOCountryCode: "us"}, nil},
+		{"empty fields are ignored", &Contact{}, nil},
+		{"changed", &Contact{BusinessName: "Acme Inc", FirstName: "Ann", ISOCountryCode: "GB"},
+			[]string{"businessName", "firstName", "isoCountryCode"}},
+	}
+	for _, tt := range tests {
+		patch, changed := contactChanges(have, &ContactFull{Contact: tt.want})
+		data, err := json.Marshal(patch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fields map[string]interface{}
+		json.Unmarshal(data, &fields)
+		if changed != (len(tt.fields) > 0) || len(fields) != len(tt.fields) {
+			t.Errorf("%s: patch = %s, changed %t, want fields %v", tt.name, data, changed, tt.fields)
+			continue
+		}
+		for _, f := range tt.fields {
+			if _, ok := fields[f]; !ok {
+				t.Errorf("%s: patch = %s, missing %s", tt.name, data, f)
+			}
+		}
+	}
+}
+
+func TestNormalizeBusinessName(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Acme, Inc.", "acme"},
+		{"ACME   Corp", "acme"},
+		{"Acme Holdings Ltd", "acme holdings"},
+		{"Acme Co. LLC", "acme"},
+		{"Inc", "inc"},
+		{"Müller GmbH", "müller"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeBusinessName(tt.in); got != tt.want {
+			t.Errorf("normalizeBusinessName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	tests := []struct {
+		name     string
+		contacts []*Contact
+		// The IDs of each group, the kept contact first, and its reasons.
+		want    [][]int64
+		reasons [][]DuplicateReason
+	}{
+		{
+			name:     "no duplicates",
+			contacts: []*Contact{{ID: 1, Email: "a@example.com"}, {ID: 2, Email: "b@example.com"}},
+		},
+		{
+			name:     "email ignoring case",
+			contacts: []*Contact{{ID: 2, Email: "A@example.com"}, {ID: 1, Email: "a@example.com "}},
+			want:     [][]int64{{1, 2}},
+			reasons:  [][]DuplicateReason{{DuplicateEmail}},
+		},
+		{
+			name: "business name in the same country",
+			contacts: []*Contact{
+				{ID: 1, BusinessName: "Acme Inc", ISOCountryCode: "US"},
+				{ID: 2, BusinessName: "ACME, LLC", ISOCountryCode: "us", PhoneNumber: "555"},
+				{ID: 3, BusinessName: "Acme", ISOCountryCode: "GB"},
+			},
+			want:    [][]int64{{2, 1}},
+			reasons: [][]DuplicateReason{{DuplicateBusinessName}},
+		},
+		{
+			name: "person name",
+			contacts: []*Contact{
+				{ID: 1, FirstName: "Ann", LastName: "Lee", ISOCountryCode: "US"},
+				{ID: 2, FirstName: "ann", LastName: "lee", ISOCountryCode: "US"},
+			},
+			want:    [][]int64{{1, 2}},
+			reasons: [][]DuplicateReason{{DuplicateName}},
+		},
+		{
+			name: "transitive",
+			contacts: []*Contact{
+				{ID: 1, Email: "a@example.com"},
+				{ID: 2, Email: "a@example.com", ExternalBusinessID: 9},
+				{ID: 3, Email: "c@example.com", ExternalBusinessID: 9},
+				{ID: 4, Email: "d@example.com"},
+			},
+			want:    [][]int64{{2, 1, 3}},
+			reasons: [][]DuplicateReason{{DuplicateEmail, DuplicateExternalBusinessID}},
+		},
+	}
+	for _, tt := range tests {
+		got := findDuplicates(tt.contacts)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: %d groups, want %d", tt.name, len(got), len(tt.want))
+			continue
+		}
+		for i, g := range got {
+			ids := []int64{g.Keep.ID}
+			for _, m := range g.Merge {
+				ids = append(ids, m.ID)
+			}
+			if len(g.Contacts) != len(ids) || !equalIDs(ids, tt.want[i]) {
+				t.Errorf("%s: group %d keeps then merges %v, want %v", tt.name, i, ids, tt.want[i])
+			}
+			if len(g.Reasons) != len(tt.reasons[i]) {
+				t.Errorf("%s: group %d reasons %v, want %v", tt.name, i, g.Reasons, tt.reasons[i])
+				continue
+			}
+			for j := range g.Reasons {
+				if g.Reasons[j] != tt.reasons[i][j] {
+					t.Errorf("%s: group %d reasons %v, want %v", tt.name, i, g.Reasons, tt.reasons[i])
+				}
+			}
+		}
+	}
+}
+
+func equalIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestUpsertListsAllOnlyAsLastResort(t *testing.T) {
+	tests := []struct {
+		name     string
+		upsert   *Contact
+		wantGets int
+	}{
+		{"found by email with the ID", &Contact{Email: "a@example.com"}, 1},
+		{"email changed", &Contact{Email: "new@example.com"}, 2},
+	}
+	for _, tt := range tests {
+		srv := &contactServer{contacts: []*Contact{{ID: 1, Email: "a@example.com", ExternalBusinessID: 7}}}
+		c := newTestClient(t, nil, srv.ServeHTTP)
+		got, _, err := c.Contacts().Upsert(context.Background(), &ContactFull{Contact: tt.upsert, ExternalBusinessID: 7})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.ID != 1 || srv.count(http.MethodGet) != tt.wantGets {
+			t.Errorf("%s: got contact %d after %d listings, want 1 after %d", tt.name, got.ID, srv.count(http.MethodGet), tt.wantGets)
+		}
+	}
+}
+
+func TestUpsertAll(t *testing.T) {
+	srv := &contactServer{contacts: []*Contact{
+		{ID: 1, Email: "old@example.com", ExternalBusinessID: 7},
+		{ID: 2, Email: "b@example.com", FirstName: "Bo"},
+		{ID: 3, Email: "dup@example.com"},
+		{ID: 4, Email: "dup@example.com"},
+	}}
+	c := newTestClient(t, nil, srv.ServeHTTP)
+	results, err := c.Contacts().UpsertAll(context.Background(), []*ContactFull{
+		{Contact: &Contact{Email: "new@example.com"}, ExternalBusinessID: 7},
+		{Contact: &Contact{Email: "b@example.com", FirstName: "Bo"}},
+		{Contact: &Contact{Email: "c@example.com", FirstName: "Cy"}},
+		{Contact: &Contact{Email: "C@example.com", FirstName: "Cy"}},
+		{Contact: &Contact{Email: "dup@example.com"}},
+		nil,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []UpsertAction{UpsertUpdated, UpsertUnchanged, UpsertCreated, UpsertUnchanged, "", ""}
+	for i, r := range results {
+		if r.Action != want[i] {
+			t.Errorf("result %d action = %q (%v), want %q", i, r.Action, r.Err, want[i])
+		}
+		if (r.Err != nil) != (want[i] == "") {
+			t.Errorf("result %d error = %v", i, r.Err)
+		}
+	}
+	if n := srv.count(http.MethodGet); n != 1 {
+		t.Errorf("contacts listed %d times, want 1", n)
+	}
+	if results[0].Contact.ID != 1 || results[0].Contact.Email != "new@example.com" {
+		t.Errorf("external ID match = %+v, want contact 1 with the new email", results[0].Contact)
+	}
+}
+
+func (s *contactServer) count(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, m := range s.methods {
+		if m == method {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,264 @@
+package veem
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact base-10 number. The zero value is 0. Decimals are
+// immutable, every operation returns a new value.
+type Decimal struct {
+	// value is the unscaled value, nil meaning 0.
+	value *big.Int
+	// scale is the number of digits after the decimal point.
+	scale int32
+}
+
+// RoundingMode determines how a Decimal is rounded when digits are dropped.
+type RoundingMode int
+
+const (
+	// RoundHalfEven rounds to the nearest neighbor, and ties to the even
+	// neighbor. Also known as banker's rounding.
+	RoundHalfEven RoundingMode = iota
+	// RoundHalfUp rounds to the nearest neighbor, and ties away from zero.
+	RoundHalfUp
+	// RoundHalfDown rounds to the nearest neighbor, and ties toward zero.
+	RoundHalfDown
+	// RoundUp rounds away from zero.
+	RoundUp
+	// RoundDown rounds toward zero, i.e. truncates.
+	RoundDown
+	// RoundCeiling rounds toward positive infinity.
+	RoundCeiling
+	// RoundFloor rounds toward negative infinity.
+	RoundFloor
+)
+
+// maxDecimalScale bounds the scale of parsed decimals, and of the exponent
+// they are written with, far beyond any amount or rate.
+const maxDecimalScale = 1000
+
+// NewDecimal returns the Decimal value * 10^-scale, e.g. NewDecimal(1050, 2)
+// is 10.50.
+func NewDecimal(value int64, scale int32) Decimal {
+	return Decimal{value: big.NewInt(value), scale: scale}
+}
+
+// ParseDecimal parses a decimal string such as "-12.345" or "1.5e3".
+func ParseDecimal(s string) (Decimal, error) {
+	orig := s
+	var exp int64
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		var err error
+		if exp, err = strconv.ParseInt(s[i+1:], 10, 32); err != nil || exp < -maxDecimalScale || exp > maxDecimalScale {
+			return Decimal{}, fmt.Errorf("invalid decimal %q", orig)
+		}
+		s = s[:i]
+	}
+	var scale int64
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		scale = int64(len(s) - i - 1)
+		s = s[:i] + s[i+1:]
+	}
+	digits := strings.TrimLeft(s, "+-")
+	if digits == "" || strings.ContainsAny(digits, "+-") {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", orig)
+	}
+	value, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", orig)
+	}
+	scale -= exp
+	if scale > maxDecimalScale {
+		return Decimal{}, fmt.Errorf("invalid decimal %q: more than %d digits after the decimal point", orig, maxDecimalScale)
+	}
+	if scale < 0 {
+		value.Mul(value, pow10(-scale))
+		scale = 0
+	}
+	return Decimal{value: value, scale: int32(scale)}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics on invalid input. It is
+// intended for constants.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// DecimalFromFloat returns the shortest Decimal that converts back to f. Use
+// it only at boundaries with code that already works in float64. NaN and
+// infinities have no Decimal and return an error.
+func DecimalFromFloat(f float64) (Decimal, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Decimal{}, fmt.Errorf("invalid decimal %v", f)
+	}
+	return ParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+func pow10(n int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(n), nil)
+}
+
+func (d Decimal) unscaled() *big.Int {
+	if d.value == nil {
+		return new(big.Int)
+	}
+	return d.value
+}
+
+// rescale returns the unscaled value of d at a scale at least d.scale.
+func (d Decimal) rescale(scale int32) *big.Int {
+	v := new(big.Int).Set(d.unscaled())
+	if scale > d.scale {
+		v.Mul(v, pow10(int64(scale-d.scale)))
+	}
+	return v
+}
+
+func maxScale(a, b Decimal) int32 {
+	if a.scale > b.scale {
+		return a.scale
+	}
+	return b.scale
+}
+
+// Add returns d + o.
+func (d Decimal) Add(o Decimal) Decimal {
+	scale := maxScale(d, o)
+	return Decimal{value: new(big.Int).Add(d.rescale(scale), o.rescale(scale)), scale: scale}
+}
+
+// Sub returns d - o.
+func (d Decimal) Sub(o Decimal) Decimal {
+	scale := maxScale(d, o)
+	return Decimal{value: new(big.Int).Sub(d.rescale(scale), o.rescale(scale)), scale: scale}
+}
+
+// Mul returns d * o, exactly.
+func (d Decimal) Mul(o Decimal) Decimal {
+	return Decimal{value: new(big.Int).Mul(d.unscaled(), o.unscaled()), scale: d.scale + o.scale}
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{value: new(big.Int).Neg(d.unscaled()), scale: d.scale}
+}
+
+// Cmp compares d and o, returning -1, 0 or +1.
+func (d Decimal) Cmp(o Decimal) int {
+	scale := maxScale(d, o)
+	return d.rescale(scale).Cmp(o.rescale(scale))
+}
+
+// Equal reports whether d and o are the same number, regardless of scale.
+func (d Decimal) Equal(o Decimal) bool { return d.Cmp(o) == 0 }
+
+// Sign returns -1, 0 or +1 depending on the sign of d.
+func (d Decimal) Sign() int { return d.unscaled().Sign() }
+
+// IsZero reports whether d is 0.
+func (d Decimal) IsZero() bool { return d.Sign() == 0 }
+
+// Scale returns the number of digits after the decimal point.
+func (d Decimal) Scale() int32 { return d.scale }
+
+// Round returns d rounded to the given number of digits after the decimal
+// point. Digits are only ever dropped, never added.
+func (d Decimal) Round(places int32, mode RoundingMode) Decimal {
+	if d.scale <= places {
+		return d
+	}
+	div := pow10(int64(d.scale - places))
+	q, r := new(big.Int).QuoRem(d.unscaled(), div, new(big.Int))
+	if r.Sign() != 0 {
+		neg := d.Sign() < 0
+		// Compare twice the remainder with the divisor to find ties.
+		half := new(big.Int).Abs(r)
+		half.Lsh(half, 1)
+		cmpHalf := half.Cmp(div)
+		var away bool
+		switch mode {
+		case RoundUp:
+			away = true
+		case RoundDown:
+			away = false
+		case RoundCeiling:
+			away = !neg
+		case RoundFloor:
+			away = neg
+		case RoundHalfUp:
+			away = cmpHalf >= 0
+		case RoundHalfDown:
+			away = cmpHalf > 0
+		default:
+			away = cmpHalf > 0 || (cmpHalf == 0 && q.Bit(0) == 1)
+		}
+		if away {
+			if neg {
+				q.Sub(q, big.NewInt(1))
+			} else {
+				q.Add(q, big.NewInt(1))
+			}
+		}
+	}
+	return Decimal{value: q, scale: places}
+}
+
+// String returns d in plain decimal notation, keeping its scale.
+func (d Decimal) String() string {
+	v := d.unscaled()
+	if d.scale <= 0 {
+		return new(big.Int).Mul(v, pow10(int64(-d.scale))).String()
+	}
+	digits := new(big.Int).Abs(v).String()
+	if pad := int(d.scale) - len(digits) + 1; pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	point := len(digits) - int(d.scale)
+	out := digits[:point] + "." + digits[point:]
+	if v.Sign() < 0 {
+		out = "-" + out
+	}
+	return out
+}
+
+// Float64 returns the nearest float64 to d.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// MarshalJSON implements json.Marshaler, encoding d as a JSON number.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting JSON numbers and
+// strings.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	s := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
@@ -0,0 +1,156 @@
+package veem
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"0", "0", false},
+		{"10.50", "10.50", false},
+		{"-12.345", "-12.345", false},
+		{"+1", "1", false},
+		{".5", "0.5", false},
+		{"1.5e3", "1500", false},
+		{"1.5E-3", "0.0015", false},
+		{"-0.001", "-0.001", false},
+		{"", "", true},
+		{"-", "", true},
+		{"1.2.3", "", true},
+		{"1-2", "", true},
+		{"abc", "", true},
+		{"1e", "", true},
+		{"1e-2147483648", "", true},
+		{"1e-2147483647", "", true},
+		{"1e2147483647", "", true},
+		{"1e1001", "", true},
+		{"1e-1001", "", true},
+		{"1e1000", "1" + zeros(1000), false},
+	}
+	for _, tt := range tests {
+		got, err := ParseDecimal(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDecimal(%q) error = %v, want error %t", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got.String() != tt.want {
+			t.Errorf("ParseDecimal(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func zeros(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = '0'
+	}
+	return string(b)
+}
+
+func TestDecimalRound(t *testing.T) {
+	tests := []struct {
+		in     string
+		places int32
+		mode   RoundingMode
+		want   string
+	}{
+		{"2.5", 0, RoundHalfEven, "2"},
+		{"3.5", 0, RoundHalfEven, "4"},
+		{"-2.5", 0, RoundHalfEven, "-2"},
+		{"2.5", 0, RoundHalfUp, "3"},
+		{"-2.5", 0, RoundHalfUp, "-3"},
+		{"2.5", 0, RoundHalfDown, "2"},
+		{"2.51", 0, RoundHalfDown, "3"},
+		{"2.1", 0, RoundUp, "3"},
+		{"-2.1", 0, RoundUp, "-3"},
+		{"2.9", 0, RoundDown, "2"},
+		{"-2.9", 0, RoundDown, "-2"},
+		{"2.1", 0, RoundCeiling, "3"},
+		{"-2.9", 0, RoundCeiling, "-2"},
+		{"2.9", 0, RoundFloor, "2"},
+		{"-2.1", 0, RoundFloor, "-3"},
+		{"1.005", 2, RoundHalfUp, "1.01"},
+		{"1.005", 2, RoundHalfEven, "1.00"},
+		{"1.5", 3, RoundHalfEven, "1.5"},
+		{"0.004", 2, RoundHalfUp, "0.00"},
+	}
+	for _, tt := range tests {
+		if got := MustParseDecimal(tt.in).Round(tt.places, tt.mode).String(); got != tt.want {
+			t.Errorf("Round(%s, %d, %d) = %s, want %s", tt.in, tt.places, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	a, b := MustParseDecimal("10.50"), MustParseDecimal("0.125")
+	tests := []struct {
+		name string
+		got  Decimal
+		want string
+	}{
+		{"add", a.Add(b), "10.625"},
+		{"sub", a.Sub(b), "10.375"},
+		{"mul", a.Mul(b), "1.31250"},
+		{"neg", a.Neg(), "-10.50"},
+		{"zero value", Decimal{}.Add(b), "0.125"},
+	}
+	for _, tt := range tests {
+		if tt.got.String() != tt.want {
+			t.Errorf("%s = %s, want %s", tt.name, tt.got, tt.want)
+		}
+	}
+	if !MustParseDecimal("1.50").Equal(MustParseDecimal("1.5")) {
+		t.Error("1.50 and 1.5 are not equal")
+	}
+	if a.Cmp(b) != 1 || b.Cmp(a) != -1 || (Decimal{}).Sign() != 0 {
+		t.Error("Cmp or Sign is wrong")
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	var v struct {
+		A Decimal `json:"a"`
+		B Decimal `json:"b"`
+		C Decimal `json:"c"`
+	}
+	if err := json.Unmarshal([]byte(`{"a":10.50,"b":"0.1","c":null}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"a":10.50,"b":0.1,"c":0}` {
+		t.Errorf("round trip = %s", out)
+	}
+	if err := json.Unmarshal([]byte(`{"a":1e-2147483647}`), &v); err == nil {
+		t.Error("unmarshalling an out of range exponent succeeded, want an error")
+	}
+}
+
+func TestDecimalFromFloat(t *testing.T) {
+	tests := []struct {
+		in      float64
+		want    string
+		wantErr bool
+	}{
+		{0.1, "0.1", false},
+		{-2.5, "-2.5", false},
+		{100, "100", false},
+		{math.NaN(), "", true},
+		{math.Inf(1), "", true},
+		{math.Inf(-1), "", true},
+	}
+	for _, tt := range tests {
+		got, err := DecimalFromFloat(tt.in)
+		if (err != nil) != tt.wantErr || err == nil && got.String() != tt.want {
+			t.Errorf("DecimalFromFloat(%v) = %s, %v, want %s, error %t", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -1,10 +1,40 @@
 package veem
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"time"
 )
 
+// Sentinel errors that an *APIError matches with errors.Is, based on the HTTP
+// status of the response.
+var (
+	// ErrNotFound matches 404 responses.
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized matches 401 responses.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden matches 403 responses.
+	ErrForbidden = errors.New("forbidden")
+	// ErrRateLimited matches 429 responses.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrValidation matches 400 and 422 responses.
+	ErrValidation = errors.New("validation failed")
+	// ErrConflict matches 409 responses.
+	ErrConflict = errors.New("conflict")
+)
+
+var statusSentinels = map[int]error{
+	http.StatusNotFound:            ErrNotFound,
+	http.StatusUnauthorized:        ErrUnauthorized,
+	http.StatusForbidden:           ErrForbidden,
+	http.StatusTooManyRequests:     ErrRateLimited,
+	http.StatusBadRequest:          ErrValidation,
+	http.StatusUnprocessableEntity: ErrValidation,
+	http.StatusConflict:            ErrConflict,
+}
+
 // APIError represents a Veem API error.
 type APIError struct {
 	// The type of the error
@@ -18,6 +48,33 @@ type APIError struct {
 	Message string `json:"message"`
 	// The time the error was produced.
 	Timestamp time.Time `json:"timestamp"`
+	// The HTTP status code of the response.
+	StatusCode int `json:"-"`
+	// The X-REQUEST-ID sent with the request.
+	RequestID string `json:"-"`
+	// The method and path of the request, e.g. "GET /veem/v1.1/contacts/1".
+	Endpoint string `json:"-"`
+	// The raw response body.
+	RawBody []byte `json:"-"`
+	// The fields rejected by a validation failure, if any.
+	FieldErrors []*FieldError `json:"fieldErrors,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Field errors are read from
+// either the fieldErrors or errors properties.
+func (a *APIError) UnmarshalJSON(data []byte) error {
+	type apiError APIError
+	aux := struct {
+		*apiError
+		Errors []*FieldError `json:"errors"`
+	}{apiError: (*apiError)(a)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if len(a.FieldErrors) == 0 {
+		a.FieldErrors = aux.Errors
+	}
+	return nil
 }
 
 // Error implements the error interface.
@@ -27,3 +84,31 @@ func (a *APIError) Error() string {
 	}
 	return fmt.Sprintf("(%s) %s", a.ErrorType, a.Message)
 }
+
+// Is reports whether the error matches one of the sentinel errors for its
+// HTTP status.
+func (a *APIError) Is(target error) bool {
+	sentinel, ok := statusSentinels[a.StatusCode]
+	return ok && sentinel == target
+}
+
+// Retryable reports whether the request that produced the error may succeed
+// if sent again.
+func (a *APIError) Retryable() bool {
+	return retryableStatuses[a.StatusCode]
+}
+
+// AuthError is returned when the client fails to obtain an access token,
+// e.g. because the credentials are invalid or Veem cannot be reached.
+type AuthError struct {
+	// The underlying error, an *APIError if Veem rejected the request.
+	Err error
+}
+
+// Error implements the error interface.
+func (a *AuthError) Error() string {
+	return fmt.Sprintf("veem authentication failed: %s", a.Err)
+}
+
+// Unwrap returns the underlying error.
+func (a *AuthError) Unwrap() error { return a.Err }
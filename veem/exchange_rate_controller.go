@@ -2,17 +2,16 @@ package veem
 
 import (
 	"bytes"
-	"encoding/json"
-	"net/http"
+	"context"
 	"time"
 )
 
 // ExchangeRateController is the interface for interacting with Veem exchange rates.
 type ExchangeRateController interface {
 	// Submits a request to generate an exchange rate quote
-	CreateQuote(quote *QuoteRequest) (*Quote, error)
+	CreateQuote(ctx context.Context, quote *QuoteRequest) (*Quote, error)
 	// Submits a request to generate multiple exchange rate quotes
-	CreateMultipleQuotes(quotes []*QuoteRequest) (*BatchQuoteResponse, error)
+	CreateMultipleQuotes(ctx context.Context, quotes []*QuoteRequest) (*BatchQuoteResponse, error)
 }
 
 type exchangeRateController struct{ *client }
@@ -24,31 +23,31 @@ type Quote struct {
 	// Time the quote expires, only valid on retrieval.
 	Expiry time.Time `json:"expiry"`
 	// The source amount.
-	FromAmount float64 `json:"fromAmount"`
+	FromAmount Decimal `json:"fromAmount"`
 	// The target amount
-	ToAmount float64 `json:"toAmount"`
+	ToAmount Decimal `json:"toAmount"`
 	// The source currency
-	FromCurrency string `json:"fromCurrency"`
+	FromCurrency CurrencyCode `json:"fromCurrency"`
 	// The target currency
-	ToCurrency string `json:"toCurrency"`
+	ToCurrency CurrencyCode `json:"toCurrency"`
 	// The rate of the exchange
-	Rate float64 `json:"rate"`
+	Rate Decimal `json:"rate"`
 }
 
 // QuoteRequest represents a request for a quote.
 type QuoteRequest struct {
 	// The source amount. Either this or ToAmount can be specified.
 	// The other is calculated.
-	FromAmount float64 `json:"fromAmount,omitempty"`
+	FromAmount *Decimal `json:"fromAmount,omitempty"`
 	// The target amount. Either this or FromAmount can be specified.
 	// The other is calculated.
-	ToAmount float64 `json:"toAmount,omitempty"`
+	ToAmount *Decimal `json:"toAmount,omitempty"`
 	// The source currency
-	FromCurrency string `json:"fromCurrency"`
+	FromCurrency CurrencyCode `json:"fromCurrency"`
 	// The target currency
-	ToCurrency string `json:"toCurrency"`
+	ToCurrency CurrencyCode `json:"toCurrency"`
 	// The destination country to ensure Veem can support the transfer.
-	ToCountry string `json:"toCountry"`
+	ToCountry CountryCode `json:"toCountry"`
 	// The email of recipient to get discounted rate
 	RecipientAccountEmail string `json:"recipientAccountEmail,omitempty"`
 }
@@ -64,12 +63,12 @@ type QuoteFailure struct {
 	ErrorCode   string `json:"errorCode"`
 }
 
-func (e *exchangeRateController) CreateQuote(quote *QuoteRequest) (*Quote, error) {
-	payload, err := json.Marshal(quote)
+func (e *exchangeRateController) CreateQuote(ctx context.Context, quote *QuoteRequest) (*Quote, error) {
+	payload, err := marshalRequest(quote)
 	if err != nil {
 		return nil, err
 	}
-	req, err := e.newRequest(http.MethodPost, "veem/v1.1/exchangerates/quotes", bytes.NewReader(payload))
+	req, err := e.newCreateRequest(ctx, "veem/v1.1/exchangerates/quotes", bytes.NewReader(payload))
 	if err != nil {
 		return nil, err
 	}
@@ -77,12 +76,12 @@ func (e *exchangeRateController) CreateQuote(quote *QuoteRequest) (*Quote, error
 	return out, e.doIntoWithAuth(req, out)
 }
 
-func (e *exchangeRateController) CreateMultipleQuotes(quotes []*QuoteRequest) (*BatchQuoteResponse, error) {
-	payload, err := json.Marshal(quotes)
+func (e *exchangeRateController) CreateMultipleQuotes(ctx context.Context, quotes []*QuoteRequest) (*BatchQuoteResponse, error) {
+	payload, err := marshalRequest(quotes)
 	if err != nil {
 		return nil, err
 	}
-	req, err := e.newRequest(http.MethodPost, "veem/v1.1/exchangerates/quotes/batch", bytes.NewReader(payload))
+	req, err := e.newCreateRequest(ctx, "veem/v1.1/exchangerates/quotes/batch", bytes.NewReader(payload))
 	if err != nil {
 		return nil, err
 	}
@@ -1,86 +1,318 @@
 package veem
 
 import (
+	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
+	"time"
 )
 
-type Filter func(*url.Values)
+// MaxPageSize is the largest page size accepted by WithPageSize. It is a
+// limit of this package rather than one documented by the Veem API, so that a
+// single request never returns an unbounded amount of data. Pages the API
+// returns with another size are still followed by Page.Next.
+const MaxPageSize = 100
 
-func WithEmail(email string) Filter {
-	return func(vals *url.Values) {
-		vals.Add("email", email)
+// Filter is a query parameter of a list endpoint. Each endpoint accepts its
+// own set of filters, see ContactFilter, PaymentFilter, InvoiceFilter and
+// CustomerFilter.
+type Filter interface {
+	apply(q *query) error
+}
+
+// ContactFilter is a filter accepted by ContactController.List.
+type ContactFilter interface {
+	Filter
+	contactFilter()
+}
+
+// PaymentFilter is a filter accepted by PaymentController.List.
+type PaymentFilter interface {
+	Filter
+	paymentFilter()
+}
+
+// InvoiceFilter is a filter accepted by InvoiceController.List.
+type InvoiceFilter interface {
+	Filter
+	invoiceFilter()
+}
+
+// CustomerFilter is a filter accepted by CustomerController.Search.
+type CustomerFilter interface {
+	Filter
+	customerFilter()
+}
+
+// query accumulates the values of a set of filters.
+type query struct {
+	vals url.Values
+	// single holds the parameters that may only be given one value.
+	single map[string]string
+	// match holds the checks of filters the API cannot apply itself, which
+	// are applied to each page of results instead.
+	match []func(item interface{}) bool
+	// ranges holds the time ranges of client-side filters on the fields a
+	// listing can be sorted by, so paging stops once a sorted listing is past
+	// them.
+	ranges map[SortField]timeRange
+}
+
+// timeRange is an inclusive range of times. A zero bound is open.
+type timeRange struct {
+	from, to time.Time
+}
+
+func newQuery() *query {
+	return &query{vals: url.Values{}, single: make(map[string]string), ranges: make(map[SortField]timeRange)}
+}
+
+// set assigns a single-valued parameter, rejecting a different value given
+// by another filter.
+func (q *query) set(key, value string) error {
+	if prev, ok := q.single[key]; ok && prev != value {
+		return fmt.Errorf("conflicting filters: %s given as both %q and %q", key, prev, value)
+	}
+	q.single[key] = value
+	q.vals.Set(key, value)
+	return nil
+}
+
+// matches reports whether the item passes every client-side check.
+func (q *query) matches(item interface{}) bool {
+	for _, m := range q.match {
+		if !m(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// narrow records a time range on a sortable field, intersecting it with any
+// range already given for the field.
+func (q *query) narrow(field SortField, from, to time.Time) {
+	r, ok := q.ranges[field]
+	if !ok || r.from.Before(from) {
+		r.from = from
+	}
+	if !ok || r.to.IsZero() || !to.IsZero() && to.Before(r.to) {
+		r.to = to
+	}
+	q.ranges[field] = r
+}
+
+// past reports whether the item, and so every item after it, is beyond the
+// time range on the field the listing is sorted by.
+func (q *query) past(item interface{}) bool {
+	field, dir, ok := strings.Cut(q.single["sort"], ":")
+	if !ok {
+		return false
+	}
+	r, ok := q.ranges[SortField(field)]
+	if !ok {
+		return false
+	}
+	t, ok := sortTime(item, SortField(field))
+	if !ok || t.IsZero() {
+		return false
+	}
+	if SortDirection(dir) == SortAscending {
+		return !r.to.IsZero() && t.After(r.to)
+	}
+	return !r.from.IsZero() && t.Before(r.from)
+}
+
+// buildQuery applies the filters in order.
+func buildQuery(filters []Filter) (*query, error) {
+	q := newQuery()
+	for _, f := range filters {
+		if err := f.apply(q); err != nil {
+			return nil, err
+		}
 	}
+	return q, nil
 }
 
-func WithFirstName(name string) Filter {
-	return func(vals *url.Values) {
-		vals.Add("firstName", name)
+// PageParam selects a page of results. It is accepted by every list
+// endpoint. A later PageParam replaces an earlier one.
+type PageParam func(q *query) error
+
+func (f PageParam) apply(q *query) error { return f(q) }
+func (PageParam) contactFilter()         {}
+func (PageParam) paymentFilter()         {}
+func (PageParam) invoiceFilter()         {}
+func (PageParam) customerFilter()        {}
+
+// PersonParam matches contacts or customers by their details.
+type PersonParam func(q *query) error
+
+func (f PersonParam) apply(q *query) error { return f(q) }
+func (PersonParam) contactFilter()         {}
+func (PersonParam) customerFilter()        {}
+
+// BatchParam matches contacts, payments or invoices created by a batch
+// operation.
+type BatchParam func(q *query) error
+
+func (f BatchParam) apply(q *query) error { return f(q) }
+func (BatchParam) contactFilter()         {}
+func (BatchParam) paymentFilter()         {}
+func (BatchParam) invoiceFilter()         {}
+
+// PaymentParam filters or sorts payments.
+type PaymentParam func(q *query) error
+
+func (f PaymentParam) apply(q *query) error { return f(q) }
+func (PaymentParam) paymentFilter()         {}
+
+// InvoiceParam filters invoices.
+type InvoiceParam func(q *query) error
+
+func (f InvoiceParam) apply(q *query) error { return f(q) }
+func (InvoiceParam) invoiceFilter()         {}
+
+// TransactionParam filters payments or invoices.
+type TransactionParam func(q *query) error
+
+func (f TransactionParam) apply(q *query) error { return f(q) }
+func (TransactionParam) paymentFilter()         {}
+func (TransactionParam) invoiceFilter()         {}
+
+func WithEmail(email string) PersonParam {
+	return func(q *query) error {
+		return q.set("email", email)
+	}
+}
+
+func WithFirstName(name string) PersonParam {
+	return func(q *query) error {
+		return q.set("firstName", name)
 	}
 }
 
-func WithLastName(name string) Filter {
-	return func(vals *url.Values) {
-		vals.Add("lastName", name)
+func WithLastName(name string) PersonParam {
+	return func(q *query) error {
+		return q.set("lastName", name)
 	}
 }
 
-func WithBusinessName(name string) Filter {
-	return func(vals *url.Values) {
-		vals.Add("businessName", name)
+func WithBusinessName(name string) PersonParam {
+	return func(q *query) error {
+		return q.set("businessName", name)
 	}
 }
 
-func WithBatchID(id int64) Filter {
-	return func(vals *url.Values) {
-		vals.Add("batchId", strconv.Itoa(int(id)))
+func WithBatchID(id int64) BatchParam {
+	return func(q *query) error {
+		return q.set("batchId", strconv.FormatInt(id, 10))
 	}
 }
 
-func WithBatchItemIDs(ids ...int64) Filter {
-	return func(vals *url.Values) {
+func WithBatchItemIDs(ids ...int64) BatchParam {
+	return func(q *query) error {
 		for _, id := range ids {
-			vals.Add("batchItemIds", strconv.Itoa(int(id)))
+			q.vals.Add("batchItemIds", strconv.FormatInt(id, 10))
 		}
+		return nil
 	}
 }
 
-func WithPageNumber(num int32) Filter {
-	return func(vals *url.Values) {
-		vals.Add("pageNumber", strconv.Itoa(int(num)))
+func WithPageNumber(num int32) PageParam {
+	return func(q *query) error {
+		if num < 0 {
+			return fmt.Errorf("invalid page number %d", num)
+		}
+		q.vals.Set("pageNumber", strconv.Itoa(int(num)))
+		return nil
 	}
 }
 
-func WithPageSize(size int32) Filter {
-	return func(vals *url.Values) {
-		vals.Add("pageSize", strconv.Itoa(int(size)))
+// WithPageSize sets the number of results per page, between 1 and
+// MaxPageSize.
+func WithPageSize(size int32) PageParam {
+	return func(q *query) error {
+		if size < 1 || size > MaxPageSize {
+			return fmt.Errorf("invalid page size %d, must be between 1 and %d", size, MaxPageSize)
+		}
+		q.vals.Set("pageSize", strconv.Itoa(int(size)))
+		return nil
 	}
 }
 
-func WithPaymentIDs(ids ...int64) Filter {
-	return func(vals *url.Values) {
+func WithPaymentIDs(ids ...int64) PaymentParam {
+	return func(q *query) error {
 		for _, id := range ids {
-			vals.Add("paymentIds", strconv.Itoa(int(id)))
+			q.vals.Add("paymentIds", strconv.FormatInt(id, 10))
 		}
+		return nil
 	}
 }
 
-func WithStatuses(statuses ...string) Filter {
-	return func(vals *url.Values) {
+func WithStatuses(statuses ...PaymentStatus) PaymentParam {
+	return func(q *query) error {
 		for _, status := range statuses {
-			vals.Add("status", status)
+			q.vals.Add("status", string(status))
 		}
+		return nil
 	}
 }
 
-func WithSortTimeUpdatedAscending() Filter {
-	return func(vals *url.Values) {
-		vals.Add("sort", "timeUpdated:asc")
+func WithInvoiceStatuses(statuses ...InvoiceStatus) InvoiceParam {
+	return func(q *query) error {
+		for _, status := range statuses {
+			q.vals.Add("status", string(status))
+		}
+		return nil
 	}
 }
 
-func WithSortTimeUpdatedDescending() Filter {
-	return func(vals *url.Values) {
-		vals.Add("sort", "timeUpdated:desc")
+// SortField is a field payments can be sorted by.
+type SortField string
+
+const (
+	SortTimeCreated SortField = "timeCreated"
+	SortTimeUpdated SortField = "timeUpdated"
+)
+
+// SortDirection is the order of a sort.
+type SortDirection string
+
+const (
+	SortAscending  SortDirection = "asc"
+	SortDescending SortDirection = "desc"
+)
+
+// WithSort sorts payments by the given field.
+func WithSort(field SortField, dir SortDirection) PaymentParam {
+	return func(q *query) error {
+		switch field {
+		case SortTimeCreated, SortTimeUpdated:
+		default:
+			return fmt.Errorf("invalid sort field %q", field)
+		}
+		switch dir {
+		case SortAscending, SortDescending:
+		default:
+			return fmt.Errorf("invalid sort direction %q", dir)
+		}
+		return q.set("sort", fmt.Sprintf("%s:%s", field, dir))
+	}
+}
+
+func WithSortTimeUpdatedAscending() PaymentParam {
+	return WithSort(SortTimeUpdated, SortAscending)
+}
+
+func WithSortTimeUpdatedDescending() PaymentParam {
+	return WithSort(SortTimeUpdated, SortDescending)
+}
+
+// toFilters converts endpoint-specific filters to their common interface.
+func toFilters[F Filter](filters []F) []Filter {
+	out := make([]Filter, len(filters))
+	for i, f := range filters {
+		out[i] = f
 	}
+	return out
 }
@@ -0,0 +1,31 @@
+package veem
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestClient returns a client talking to a fake API served by handler.
+// Token requests are answered with a fixed token.
+func newTestClient(t *testing.T, opts *ClientOptions, handler http.HandlerFunc) *client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/token" {
+			w.Write([]byte(`{"access_token":"token","token_type":"bearer","expires_in":3600}`))
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	if opts == nil {
+		opts = &ClientOptions{}
+	}
+	opts.BaseURL = srv.URL
+	opts.LazyAuth = true
+	c, err := New(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c.(*client)
+}
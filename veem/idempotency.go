@@ -0,0 +1,108 @@
+package veem
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// ErrDuplicateRequest is returned when a request is refused because the
+// IdempotencyLedger shows a request with the same key already succeeded.
+var ErrDuplicateRequest = errors.New("a request with this idempotency key already succeeded")
+
+type (
+	requestIDKey     struct{}
+	createRequestKey struct{}
+)
+
+// requestKey is a caller-supplied request ID and the request it was bound to.
+type requestKey struct {
+	id string
+
+	mu sync.Mutex
+	// endpoint is the URL of the first Create sent with the ID.
+	endpoint string
+}
+
+// WithRequestID returns a context that makes the first Create call made with
+// it, such as Payments().Create or Invoices().CreateBatch, use the given
+// X-REQUEST-ID. Veem uses the ID to de-duplicate requests, so reusing the
+// same ID when re-running a Create after a timeout will not create the
+// resource twice. The ID is kept across the client's own retries and is
+// reused by later Creates of the same kind with the same context. Every
+// other request, such as authenticating, uploading attachments or the
+// lookups made by Upsert, gets its own ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, &requestKey{id: id})
+}
+
+// RequestIDFromContext returns the request ID set by WithRequestID, if any.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	k, ok := ctx.Value(requestIDKey{}).(*requestKey)
+	if !ok || k.id == "" {
+		return "", false
+	}
+	return k.id, true
+}
+
+// newCreateRequest returns a POST request creating resources, which is sent
+// with the caller-supplied request ID, if any.
+func (c *client) newCreateRequest(ctx context.Context, endpoint string, body io.Reader) (*http.Request, error) {
+	return c.newRequest(context.WithValue(ctx, createRequestKey{}, true), http.MethodPost, endpoint, body)
+}
+
+// requestIDFor returns the caller-supplied ID to send with req, if the
+// context has one and req is the Create it is bound to.
+func requestIDFor(req *http.Request) (string, bool) {
+	k, ok := req.Context().Value(requestIDKey{}).(*requestKey)
+	if !ok || k.id == "" {
+		return "", false
+	}
+	if create, _ := req.Context().Value(createRequestKey{}).(bool); !create {
+		return "", false
+	}
+	endpoint := req.URL.String()
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.endpoint == "" {
+		k.endpoint = endpoint
+	}
+	return k.id, k.endpoint == endpoint
+}
+
+// IdempotencyLedger records the request IDs of successful requests so they
+// are never sent twice. Only Creates sent with an ID from WithRequestID
+// consult the ledger.
+type IdempotencyLedger interface {
+	// Succeeded reports whether a request with the given ID already succeeded.
+	Succeeded(ctx context.Context, id string) (bool, error)
+	// MarkSucceeded records that the request with the given ID succeeded.
+	MarkSucceeded(ctx context.Context, id string) error
+}
+
+// NewMemoryLedger returns an IdempotencyLedger that keeps request IDs in
+// memory for the life of the process.
+func NewMemoryLedger() IdempotencyLedger {
+	return &memoryLedger{ids: make(map[string]struct{})}
+}
+
+type memoryLedger struct {
+	mu  sync.Mutex
+	ids map[string]struct{}
+}
+
+func (m *memoryLedger) Succeeded(ctx context.Context, id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.ids[id]
+	return ok, nil
+}
+
+func (m *memoryLedger) MarkSucceeded(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ids[id] = struct{}{}
+	return nil
+}
@@ -0,0 +1,81 @@
+package veem
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestRequestIDOnlyOnBoundPost(t *testing.T) {
+	var mu sync.Mutex
+	var got []string
+	c := newTestClient(t, &ClientOptions{IdempotencyLedger: NewMemoryLedger()}, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		got = append(got, r.Method+" "+r.Header.Get(requestIDHeader))
+		mu.Unlock()
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"content":[],"last":true}`))
+			return
+		}
+		w.Write([]byte(`{"id":1}`))
+	})
+	ctx := WithRequestID(context.Background(), "key-1")
+	contact := &ContactFull{Contact: &Contact{Email: "a@example.com"}}
+	if _, _, err := c.Contacts().Upsert(ctx, contact); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] == "GET key-1" || got[1] != "POST key-1" {
+		t.Fatalf("requests = %v, want a fresh ID for the GET and key-1 for the POST", got)
+	}
+	if _, err := c.Contacts().Create(ctx, contact); !errors.Is(err, ErrDuplicateRequest) {
+		t.Errorf("re-running the Create with the same key = %v, want ErrDuplicateRequest", err)
+	}
+	if _, err := c.Payments().Create(ctx, &DraftPayment{}); err != nil {
+		t.Errorf("POST to another endpoint = %v, want a fresh ID", err)
+	}
+	if last := got[len(got)-1]; last == "POST key-1" {
+		t.Errorf("POST to another endpoint reused the key")
+	}
+}
+
+func TestRequestIDSkipsAuthAndUploads(t *testing.T) {
+	var mu sync.Mutex
+	ids := make(map[string]string)
+	c := newTestClient(t, &ClientOptions{IdempotencyLedger: NewMemoryLedger()}, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ids[r.URL.Path] = r.Header.Get(requestIDHeader)
+		mu.Unlock()
+		w.Write([]byte(`{"id":1}`))
+	})
+	path := filepath.Join(t.TempDir(), "INV-0001.pdf")
+	if err := os.WriteFile(path, []byte("%PDF-1.4"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := WithRequestID(context.Background(), "key-1")
+	if err := c.Authenticate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Attachments().Upload(ctx, path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Payments().Create(ctx, &DraftPayment{}); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	upload, payment := ids["/veem/v1.1/attachments"], ids["/veem/v1.1/payments"]
+	mu.Unlock()
+	if upload == "" || upload == "key-1" {
+		t.Errorf("upload request ID = %q, want a fresh ID", upload)
+	}
+	if payment != "key-1" {
+		t.Errorf("payment request ID = %q, want key-1", payment)
+	}
+	if _, err := c.Payments().Create(ctx, &DraftPayment{}); !errors.Is(err, ErrDuplicateRequest) {
+		t.Errorf("re-running the Create with the same key = %v, want ErrDuplicateRequest", err)
+	}
+}
@@ -2,7 +2,7 @@ package veem
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -11,11 +11,21 @@ import (
 // InvoiceController is the interface for interacting with Veem invoices.
 type InvoiceController interface {
 	// Post an invoice and send to a receiver
-	Create(inv *Invoice) (*Invoice, error)
+	Create(ctx context.Context, inv *Invoice) (*Invoice, error)
+	// Check an invoice against the country-currency metadata before creating it
+	Preflight(ctx context.Context, inv *Invoice) ([]*FieldError, error)
 	// Retrieve an invoice
-	Get(id int64) (*Invoice, error)
+	Get(ctx context.Context, id int64) (*Invoice, error)
+	// Get invoices for this account with filters
+	List(ctx context.Context, filters ...InvoiceFilter) (*ListInvoicesResponse, error)
+	// Iterate over every invoice for this account matching the filters
+	All(ctx context.Context, filters ...InvoiceFilter) *InvoiceIterator
+	// Create a batch of invoices
+	CreateBatch(ctx context.Context, invoices []*Invoice, includeItems bool) (*BatchOperation, error)
+	// Get the status of a batch operation
+	GetBatch(ctx context.Context, batchID int64, includeItems bool) (*BatchOperation, error)
 	// Cancel an invoice
-	Cancel(id int64) (*Invoice, error)
+	Cancel(ctx context.Context, id int64) (*Invoice, error)
 }
 
 type Invoice struct {
@@ -30,20 +40,24 @@ type Invoice struct {
 	PurposeOfPayment     string        `json:"purposeOfPayment,omitempty"`
 
 	// Populated on retrieval
-	ID          int64      `json:"id,omitempty"`
-	Status      string     `json:"status,omitempty"`
-	TimeCreated *time.Time `json:"timeCreated,omitempty"`
-	ClaimLink   string     `json:"claimLink,omitempty"`
+	ID          int64         `json:"id,omitempty"`
+	Status      InvoiceStatus `json:"status,omitempty"`
+	TimeCreated *time.Time    `json:"timeCreated,omitempty"`
+	ClaimLink   string        `json:"claimLink,omitempty"`
+	BatchItemID int64         `json:"batchItemId,omitempty"`
 }
 
 type invoiceController struct{ *client }
 
-func (i *invoiceController) Create(inv *Invoice) (*Invoice, error) {
-	payload, err := json.Marshal(inv)
+// ListInvoicesResponse is a page of invoices.
+type ListInvoicesResponse = Page[*Invoice]
+
+func (i *invoiceController) Create(ctx context.Context, inv *Invoice) (*Invoice, error) {
+	payload, err := marshalRequest(inv)
 	if err != nil {
 		return nil, err
 	}
-	req, err := i.newRequest(http.MethodPost, "veem/v1.1/invoices", bytes.NewReader(payload))
+	req, err := i.newCreateRequest(ctx, "veem/v1.1/invoices", bytes.NewReader(payload))
 	if err != nil {
 		return nil, err
 	}
@@ -51,8 +65,8 @@ func (i *invoiceController) Create(inv *Invoice) (*Invoice, error) {
 	return out, i.doIntoWithAuth(req, out)
 }
 
-func (i *invoiceController) Get(id int64) (*Invoice, error) {
-	req, err := i.newRequest(http.MethodGet, fmt.Sprintf("veem/v1.1/invoices/%d", id), nil)
+func (i *invoiceController) Get(ctx context.Context, id int64) (*Invoice, error) {
+	req, err := i.newRequest(ctx, http.MethodGet, fmt.Sprintf("veem/v1.1/invoices/%d", id), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -60,8 +74,40 @@ func (i *invoiceController) Get(id int64) (*Invoice, error) {
 	return out, i.doIntoWithAuth(req, out)
 }
 
-func (i *invoiceController) Cancel(id int64) (*Invoice, error) {
-	req, err := i.newRequest(http.MethodPost, fmt.Sprintf("veem/v1.1/invoices/%d/cancel", id), nil)
+func (i *invoiceController) List(ctx context.Context, filters ...InvoiceFilter) (*ListInvoicesResponse, error) {
+	return listPage[*Invoice](ctx, i.client, "veem/v1.1/invoices", toFilters(filters))
+}
+
+func (i *invoiceController) All(ctx context.Context, filters ...InvoiceFilter) *InvoiceIterator {
+	return &InvoiceIterator{ctx: ctx, first: func() (*ListInvoicesResponse, error) { return i.List(ctx, filters...) }}
+}
+
+func (i *invoiceController) CreateBatch(ctx context.Context, invoices []*Invoice, includeItems bool) (*BatchOperation, error) {
+	payload, err := marshalRequest(invoices)
+	if err != nil {
+		return nil, err
+	}
+	ep := fmt.Sprintf("veem/v1.1/invoices/batch?includeItems=%t", includeItems)
+	req, err := i.newCreateRequest(ctx, ep, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	out := &BatchOperation{}
+	return out, i.doIntoWithAuth(req, out)
+}
+
+func (i *invoiceController) GetBatch(ctx context.Context, batchID int64, includeItems bool) (*BatchOperation, error) {
+	ep := fmt.Sprintf("veem/v1.1/invoices/batch/%d?includeItems=%t", batchID, includeItems)
+	req, err := i.newRequest(ctx, http.MethodGet, ep, nil)
+	if err != nil {
+		return nil, err
+	}
+	out := &BatchOperation{}
+	return out, i.doIntoWithAuth(req, out)
+}
+
+func (i *invoiceController) Cancel(ctx context.Context, id int64) (*Invoice, error) {
+	req, err := i.newRequest(ctx, http.MethodPost, fmt.Sprintf("veem/v1.1/invoices/%d/cancel", id), nil)
 	if err != nil {
 		return nil, err
 	}
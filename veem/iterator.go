@@ -0,0 +1,65 @@
+package veem
+
+import "context"
+
+// Iterator walks every item of a list endpoint, fetching pages as needed.
+// Call Next until it returns false, then check Err.
+//
+//	it := client.Contacts().All(ctx, veem.WithBusinessName("Acme"))
+//	for it.Next() {
+//		fmt.Println(it.Item())
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// It is safe to stop calling Next at any time.
+type Iterator[T any] struct {
+	ctx     context.Context
+	first   func() (*Page[T], error)
+	page    *Page[T]
+	idx     int
+	current T
+	err     error
+}
+
+// ContactIterator iterates over contacts.
+type ContactIterator = Iterator[*Contact]
+
+// PaymentIterator iterates over payments.
+type PaymentIterator = Iterator[*Payment]
+
+// InvoiceIterator iterates over invoices.
+type InvoiceIterator = Iterator[*Invoice]
+
+// CustomerIterator iterates over customers.
+type CustomerIterator = Iterator[*Customer]
+
+// Next advances to the next item, returning false when there are no more or
+// an error occurred.
+func (it *Iterator[T]) Next() bool {
+	for it.err == nil {
+		if it.page != nil && it.idx < len(it.page.Items) {
+			it.current = it.page.Items[it.idx]
+			it.idx++
+			return true
+		}
+		if it.page == nil {
+			it.page, it.err = it.first()
+		} else if it.page.Last || it.page.received == 0 {
+			break
+		} else {
+			it.page, it.err = it.page.Next(it.ctx)
+		}
+		it.idx = 0
+	}
+	var zero T
+	it.current = zero
+	return false
+}
+
+// Item returns the current item.
+func (it *Iterator[T]) Item() T { return it.current }
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator[T]) Err() error { return it.err }
@@ -1,6 +1,7 @@
 package veem
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 )
@@ -8,33 +9,35 @@ import (
 // MetaController is the interface for accessing veem metadata.
 type MetaController interface {
 	// Returns a list of countries supported and currencies for each
-	CountryCurrencyMap(bankFields bool) ([]*CountryCurrentMap, error)
+	CountryCurrencyMap(ctx context.Context, bankFields bool) ([]*CountryCurrentMap, error)
+	// Checks that Veem supports receiving, or sending, a currency in a country
+	CheckCurrency(ctx context.Context, country CountryCode, currency CurrencyCode, receiving bool) error
 }
 
 type metaController struct{ *client }
 
 type CountryCurrentMap struct {
 	BankFields                []string          `json:"bankFields"`
-	Country                   string            `json:"country"`
+	Country                   CountryCode       `json:"country"`
 	CountryName               string            `json:"countryName"`
 	InvoiceAttachmentRequired bool              `json:"invoiceAttachmentRequired"`
 	PurposeOfPaymentRequired  bool              `json:"purposeOfPaymentRequired"`
 	PurposeOfPaymentInfo      []*PaymentPurpose `json:"purposeOfPaymentInfo"`
-	ReceivingCurrencies       []string          `json:"receivingCurrencies"`
-	SendingCurrencies         []string          `json:"sendingCurrencies"`
+	ReceivingCurrencies       []CurrencyCode    `json:"receivingCurrencies"`
+	SendingCurrencies         []CurrencyCode    `json:"sendingCurrencies"`
 }
 
 type PaymentPurpose struct {
-	CountryCode string `json:"countryCode"`
-	Description string `json:"description"`
-	Industry    string `json:"industry"`
-	SubIndustry string `json:"subindustry"`
-	PurposeCode string `json:"purposeCode"`
+	CountryCode CountryCode `json:"countryCode"`
+	Description string      `json:"description"`
+	Industry    string      `json:"industry"`
+	SubIndustry string      `json:"subindustry"`
+	PurposeCode string      `json:"purposeCode"`
 }
 
-func (m *metaController) CountryCurrencyMap(bankFields bool) ([]*CountryCurrentMap, error) {
+func (m *metaController) CountryCurrencyMap(ctx context.Context, bankFields bool) ([]*CountryCurrentMap, error) {
 	ep := fmt.Sprintf("veem/public/v1.1/country-currency-map?bankFields=%t", bankFields)
-	req, err := m.newRequest(http.MethodGet, ep, nil)
+	req, err := m.newRequest(ctx, http.MethodGet, ep, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,260 @@
+package veem
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMetadataTTL is how long metadata is used before it is refreshed
+	// when the ClientOptions do not set MetadataTTL.
+	DefaultMetadataTTL = 24 * time.Hour
+	// metadataFetchTimeout bounds each refresh, which like token refreshes
+	// are shared between callers.
+	metadataFetchTimeout = 30 * time.Second
+	// metadataRetryBackoff is how long to wait after a failed background
+	// refresh before trying again, while stale metadata is still returned.
+	metadataRetryBackoff = 5 * time.Minute
+)
+
+// Metadata is a snapshot of the country-currency map, including bank fields,
+// indexed for lookups. It must not be modified.
+type Metadata struct {
+	Countries []*CountryCurrentMap `json:"countries"`
+	FetchedAt time.Time            `json:"fetchedAt"`
+
+	byCountry   map[CountryCode]*CountryCurrentMap
+	byReceiving map[CurrencyCode][]*CountryCurrentMap
+	bySending   map[CurrencyCode][]*CountryCurrentMap
+}
+
+// NewMetadata indexes the country-currency map fetched at the given time.
+func NewMetadata(countries []*CountryCurrentMap, fetchedAt time.Time) *Metadata {
+	m := &Metadata{
+		Countries:   countries,
+		FetchedAt:   fetchedAt,
+		byCountry:   make(map[CountryCode]*CountryCurrentMap, len(countries)),
+		byReceiving: make(map[CurrencyCode][]*CountryCurrentMap),
+		bySending:   make(map[CurrencyCode][]*CountryCurrentMap),
+	}
+	for _, c := range countries {
+		m.byCountry[CountryCode(strings.ToUpper(string(c.Country)))] = c
+		for _, cur := range c.ReceivingCurrencies {
+			key := CurrencyCode(strings.ToUpper(string(cur)))
+			m.byReceiving[key] = append(m.byReceiving[key], c)
+		}
+		for _, cur := range c.SendingCurrencies {
+			key := CurrencyCode(strings.ToUpper(string(cur)))
+			m.bySending[key] = append(m.bySending[key], c)
+		}
+	}
+	return m
+}
+
+// LoadMetadata reads a snapshot written by Metadata.Save.
+func LoadMetadata(path string) (*Metadata, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var snap Metadata
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, err
+	}
+	return NewMetadata(snap.Countries, snap.FetchedAt), nil
+}
+
+// Save writes the snapshot to path, e.g. for use offline with LoadMetadata.
+func (m *Metadata) Save(path string) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// Country returns the metadata of a country, or false if Veem does not
+// support it.
+func (m *Metadata) Country(code CountryCode) (*CountryCurrentMap, bool) {
+	c, ok := m.byCountry[CountryCode(strings.ToUpper(string(code)))]
+	return c, ok
+}
+
+// ReceivingCountries returns the countries whose payees can receive the
+// currency.
+func (m *Metadata) ReceivingCountries(currency CurrencyCode) []*CountryCurrentMap {
+	return m.byReceiving[CurrencyCode(strings.ToUpper(string(currency)))]
+}
+
+// SendingCountries returns the countries whose payers can send the currency.
+func (m *Metadata) SendingCountries(currency CurrencyCode) []*CountryCurrentMap {
+	return m.bySending[CurrencyCode(strings.ToUpper(string(currency)))]
+}
+
+// CheckCurrency is like the CheckCurrency function for this snapshot.
+func (m *Metadata) CheckCurrency(country CountryCode, currency CurrencyCode, receiving bool) error {
+	if c, ok := m.Country(country); ok {
+		if (receiving && c.CanReceive(currency)) || (!receiving && c.CanSend(currency)) {
+			return nil
+		}
+	}
+	return &UnsupportedCurrencyError{Country: country, Currency: currency, Receiving: receiving}
+}
+
+// Purposes returns the purpose of payment codes of a country for an industry
+// and sub-industry, compared case-insensitively. An empty industry or
+// sub-industry matches any.
+func (m *Metadata) Purposes(country CountryCode, industry, subIndustry string) []*PaymentPurpose {
+	c, ok := m.Country(country)
+	if !ok {
+		return nil
+	}
+	out := make([]*PaymentPurpose, 0)
+	for _, p := range c.PurposeOfPaymentInfo {
+		if industry != "" && !strings.EqualFold(p.Industry, industry) {
+			continue
+		}
+		if subIndustry != "" && !strings.EqualFold(p.SubIndustry, subIndustry) {
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// Industries returns the distinct industries of the purpose of payment codes
+// of a country, in the order the API lists them.
+func (m *Metadata) Industries(country CountryCode) []string {
+	c, ok := m.Country(country)
+	if !ok {
+		return nil
+	}
+	seen := make(map[string]bool)
+	out := make([]string, 0)
+	for _, p := range c.PurposeOfPaymentInfo {
+		if !seen[p.Industry] {
+			seen[p.Industry] = true
+			out = append(out, p.Industry)
+		}
+	}
+	return out
+}
+
+// MetadataCache holds the country-currency map for a client. Metadata older
+// than the TTL is still returned while a fresh copy is fetched in the
+// background, and is kept if fetching fails, so a snapshot loaded from disk
+// keeps working offline.
+type MetadataCache struct {
+	fetch func(ctx context.Context) ([]*CountryCurrentMap, error)
+	ttl   time.Duration
+
+	mu      sync.Mutex
+	current *Metadata
+	refresh *metadataRefresh
+	// retryAt is when a background refresh may be tried again after one
+	// failed.
+	retryAt time.Time
+}
+
+// metadataRefresh is an in-flight fetch shared by all waiters.
+type metadataRefresh struct {
+	done chan struct{}
+	meta *Metadata
+	err  error
+}
+
+func newMetadataCache(meta MetaController, ttl time.Duration) *MetadataCache {
+	if ttl <= 0 {
+		ttl = DefaultMetadataTTL
+	}
+	return &MetadataCache{
+		fetch: func(ctx context.Context) ([]*CountryCurrentMap, error) {
+			return meta.CountryCurrencyMap(ctx, true)
+		},
+		ttl: ttl,
+	}
+}
+
+// Get returns the cached metadata, fetching it if there is none yet. Metadata
+// older than the TTL is refreshed in the background, and a failed background
+// refresh is only retried after metadataRetryBackoff.
+func (m *MetadataCache) Get(ctx context.Context) (*Metadata, error) {
+	m.mu.Lock()
+	cur := m.current
+	if cur == nil {
+		r := m.startRefresh()
+		m.mu.Unlock()
+		return r.wait(ctx)
+	}
+	if time.Since(cur.FetchedAt) >= m.ttl && !time.Now().Before(m.retryAt) {
+		m.startRefresh()
+	}
+	m.mu.Unlock()
+	return cur, nil
+}
+
+// Refresh fetches the metadata now and returns it.
+func (m *MetadataCache) Refresh(ctx context.Context) (*Metadata, error) {
+	m.mu.Lock()
+	r := m.startRefresh()
+	m.mu.Unlock()
+	return r.wait(ctx)
+}
+
+// Set replaces the cached metadata, e.g. with a snapshot from LoadMetadata.
+func (m *MetadataCache) Set(meta *Metadata) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.current = meta
+}
+
+// Load replaces the cached metadata with the snapshot at path.
+func (m *MetadataCache) Load(path string) error {
+	meta, err := LoadMetadata(path)
+	if err != nil {
+		return err
+	}
+	m.Set(meta)
+	return nil
+}
+
+// startRefresh returns the in-flight refresh, starting one if needed. It must
+// be called with mu held.
+func (m *MetadataCache) startRefresh() *metadataRefresh {
+	if m.refresh != nil {
+		return m.refresh
+	}
+	r := &metadataRefresh{done: make(chan struct{})}
+	m.refresh = r
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), metadataFetchTimeout)
+		defer cancel()
+		countries, err := m.fetch(ctx)
+		m.mu.Lock()
+		if err == nil {
+			r.meta = NewMetadata(countries, time.Now())
+			m.current = r.meta
+			m.retryAt = time.Time{}
+		} else {
+			m.retryAt = time.Now().Add(metadataRetryBackoff)
+		}
+		m.refresh = nil
+		m.mu.Unlock()
+		r.err = err
+		close(r.done)
+	}()
+	return r
+}
+
+func (r *metadataRefresh) wait(ctx context.Context) (*Metadata, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-r.done:
+		return r.meta, r.err
+	}
+}
@@ -0,0 +1,116 @@
+package veem
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMetadataCacheSingleFlight(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	m := &MetadataCache{ttl: time.Hour, fetch: func(ctx context.Context) ([]*CountryCurrentMap, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return []*CountryCurrentMap{{Country: "US"}}, nil
+	}}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if meta, err := m.Get(context.Background()); err != nil || len(meta.Countries) != 1 {
+				t.Errorf("Get() = %v, %v", meta, err)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if calls != 1 {
+		t.Errorf("fetch called %d times, want 1", calls)
+	}
+}
+
+func TestMetadataCacheServesStaleWhileRefreshing(t *testing.T) {
+	release := make(chan struct{})
+	m := &MetadataCache{ttl: time.Hour, fetch: func(ctx context.Context) ([]*CountryCurrentMap, error) {
+		<-release
+		return []*CountryCurrentMap{{Country: "US"}, {Country: "GB"}}, nil
+	}}
+	stale := NewMetadata([]*CountryCurrentMap{{Country: "US"}}, time.Now().Add(-2*time.Hour))
+	m.Set(stale)
+	if got, err := m.Get(context.Background()); err != nil || got != stale {
+		t.Fatalf("Get() = %v, %v, want the stale metadata", got, err)
+	}
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for {
+		got, err := m.Get(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got.Countries) == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the metadata was never refreshed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestMetadataCacheBacksOffAfterFailure(t *testing.T) {
+	var calls int32
+	m := &MetadataCache{ttl: time.Hour, fetch: func(ctx context.Context) ([]*CountryCurrentMap, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, errors.New("offline")
+	}}
+	// An offline snapshot older than the TTL keeps being used.
+	path := filepath.Join(t.TempDir(), "metadata.json")
+	if err := NewMetadata([]*CountryCurrentMap{{Country: "US"}}, time.Now().Add(-48*time.Hour)).Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 50; i++ {
+		meta, err := m.Get(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := meta.Country("US"); !ok {
+			t.Fatal("the snapshot was replaced")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("fetch called %d times, want 1", n)
+	}
+
+	// Once the backoff has passed the refresh is tried again.
+	m.mu.Lock()
+	m.retryAt = time.Now().Add(-time.Second)
+	m.mu.Unlock()
+	m.Get(context.Background())
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&calls) != 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("the refresh was not retried after the backoff")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestMetadataCacheRefreshError(t *testing.T) {
+	m := &MetadataCache{ttl: time.Hour, fetch: func(ctx context.Context) ([]*CountryCurrentMap, error) {
+		return nil, errors.New("offline")
+	}}
+	if _, err := m.Get(context.Background()); err == nil {
+		t.Error("Get() with nothing cached and a failing fetch succeeded")
+	}
+}
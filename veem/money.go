@@ -0,0 +1,99 @@
+package veem
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Money is an exact amount in a currency, as used throughout the Veem API.
+type Money struct {
+	Currency CurrencyCode `json:"currency"`
+	Number   Decimal      `json:"number"`
+}
+
+// Amount is the name the API models use for Money.
+type Amount = Money
+
+// CurrencyMismatchError is returned when combining Money in different
+// currencies.
+type CurrencyMismatchError struct {
+	A, B CurrencyCode
+}
+
+// Error implements the error interface.
+func (c *CurrencyMismatchError) Error() string {
+	return fmt.Sprintf("currency mismatch: %s and %s", c.A, c.B)
+}
+
+// NewMoney parses the amount in the given currency, e.g.
+// NewMoney("USD", "10.50").
+func NewMoney(currency CurrencyCode, amount string) (Money, error) {
+	d, err := ParseDecimal(amount)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Currency: currency, Number: d}, nil
+}
+
+// MustMoney is like NewMoney but panics on an invalid amount.
+func MustMoney(currency CurrencyCode, amount string) Money {
+	m, err := NewMoney(currency, amount)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func (m Money) checkCurrency(o Money) error {
+	if !strings.EqualFold(string(m.Currency), string(o.Currency)) {
+		return &CurrencyMismatchError{A: m.Currency, B: o.Currency}
+	}
+	return nil
+}
+
+// Add returns m + o. Both must be in the same currency.
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.checkCurrency(o); err != nil {
+		return Money{}, err
+	}
+	return Money{Currency: m.Currency, Number: m.Number.Add(o.Number)}, nil
+}
+
+// Sub returns m - o. Both must be in the same currency.
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.checkCurrency(o); err != nil {
+		return Money{}, err
+	}
+	return Money{Currency: m.Currency, Number: m.Number.Sub(o.Number)}, nil
+}
+
+// Cmp compares m and o, returning -1, 0 or +1. Both must be in the same
+// currency.
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.checkCurrency(o); err != nil {
+		return 0, err
+	}
+	return m.Number.Cmp(o.Number), nil
+}
+
+// Mul returns m multiplied by factor, rounded to the precision of the
+// currency with the given mode.
+func (m Money) Mul(factor Decimal, mode RoundingMode) Money {
+	return Money{Currency: m.Currency, Number: m.Number.Mul(factor)}.Round(mode)
+}
+
+// Convert returns m converted to another currency at the given rate, rounded
+// to the precision of that currency with the given mode.
+func (m Money) Convert(rate Decimal, currency CurrencyCode, mode RoundingMode) Money {
+	return Money{Currency: currency, Number: m.Number.Mul(rate)}.Round(mode)
+}
+
+// Round returns m rounded to the precision of its currency.
+func (m Money) Round(mode RoundingMode) Money {
+	return Money{Currency: m.Currency, Number: m.Number.Round(m.Currency.MinorUnits(), mode)}
+}
+
+// String returns m as e.g. "10.50 USD".
+func (m Money) String() string {
+	return fmt.Sprintf("%s %s", m.Number, m.Currency)
+}
@@ -0,0 +1,100 @@
+package veem
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+)
+
+// PKCE holds a Proof Key for Code Exchange verifier and its challenge for
+// the authorization code flow.
+type PKCE struct {
+	// Verifier is kept secret and sent with Exchange.
+	Verifier string
+	// Challenge is derived from Verifier and sent with AuthCodeURL.
+	Challenge string
+	// Method is the challenge method, always "S256".
+	Method string
+}
+
+// NewPKCE generates a random PKCE verifier and its S256 challenge.
+func NewPKCE() (*PKCE, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	verifier := base64.RawURLEncoding.EncodeToString(buf)
+	sum := sha256.Sum256([]byte(verifier))
+	return &PKCE{
+		Verifier:  verifier,
+		Challenge: base64.RawURLEncoding.EncodeToString(sum[:]),
+		Method:    "S256",
+	}, nil
+}
+
+// AuthCodeURL returns the URL to send a user to so they can authorize the
+// app to act on their Veem account. The state is returned unchanged to the
+// RedirectURL and should be verified there. pkce may be nil.
+func (o *ClientOptions) AuthCodeURL(state string, pkce *PKCE) (string, error) {
+	u, err := o.apiURL()
+	if err != nil {
+		return "", err
+	}
+	vals := url.Values{}
+	vals.Add("response_type", "code")
+	vals.Add("client_id", o.ClientID)
+	vals.Add("scope", o.scope())
+	if o.RedirectURL != "" {
+		vals.Add("redirect_uri", o.RedirectURL)
+	}
+	if state != "" {
+		vals.Add("state", state)
+	}
+	if pkce != nil {
+		vals.Add("code_challenge", pkce.Challenge)
+		vals.Add("code_challenge_method", pkce.Method)
+	}
+	return fmt.Sprintf("%s/oauth/authorize?%s", u.String(), vals.Encode()), nil
+}
+
+// Exchange trades the authorization code received at the RedirectURL for a
+// user token. pkce must be the one passed to AuthCodeURL, if any. The
+// returned token can be stored and used as the UserToken of a Client.
+func (o *ClientOptions) Exchange(ctx context.Context, code string, pkce *PKCE) (*AccessTokenResponse, error) {
+	c, err := o.tokenClient()
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	form.Add("grant_type", "authorization_code")
+	form.Add("code", code)
+	if o.RedirectURL != "" {
+		form.Add("redirect_uri", o.RedirectURL)
+	}
+	if pkce != nil {
+		form.Add("code_verifier", pkce.Verifier)
+	}
+	return c.requestToken(ctx, form)
+}
+
+// Refresh trades a refresh token for a new user token. Clients created with
+// a UserToken do this automatically.
+func (o *ClientOptions) Refresh(ctx context.Context, refreshToken string) (*AccessTokenResponse, error) {
+	c, err := o.tokenClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.refreshAccessToken(ctx, refreshToken)
+}
+
+// tokenClient returns an unauthenticated client for the token endpoint.
+func (o *ClientOptions) tokenClient() (*client, error) {
+	u, err := o.apiURL()
+	if err != nil {
+		return nil, err
+	}
+	return &client{opts: o, apiURL: u, client: o.httpClient()}, nil
+}
@@ -0,0 +1,121 @@
+package veem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrNoMorePages is returned by Page.Next when called on the last page.
+var ErrNoMorePages = errors.New("no more pages left")
+
+// Page is a single page of results from a list endpoint.
+type Page[T any] struct {
+	Items []T `json:"content"`
+
+	First            bool  `json:"first"`
+	Last             bool  `json:"last"`
+	NumberOfElements int   `json:"numberOfElements"`
+	TotalElements    int   `json:"totalElements"`
+	PageNumber       int32 `json:"number"`
+	PageSize         int32 `json:"size"`
+	TotalPages       int   `json:"totalPages"`
+
+	fetch   func(ctx context.Context, filters ...Filter) (*Page[T], error)
+	filters []Filter
+	// received is the number of items returned by the API, before any
+	// client-side filters were applied.
+	received int
+}
+
+// HasNext reports whether there are pages after this one.
+func (p *Page[T]) HasNext() bool { return !p.Last }
+
+// Len returns the number of items on this page, after any client-side
+// filters were applied.
+func (p *Page[T]) Len() int { return len(p.Items) }
+
+// Total returns the number of items across all pages as reported by the API,
+// before any client-side filters are applied.
+func (p *Page[T]) Total() int { return p.TotalElements }
+
+// Next retrieves the page after this one with the same filters, including
+// the page size the caller asked for. It returns ErrNoMorePages on the last
+// page.
+func (p *Page[T]) Next(ctx context.Context) (*Page[T], error) {
+	return p.page(ctx, p.PageNumber+1)
+}
+
+// page retrieves the page with the given number using the same filters as
+// this one. The page size is only sent if the caller set one, so pages keep
+// the API's default size otherwise.
+func (p *Page[T]) page(ctx context.Context, number int32) (*Page[T], error) {
+	if p.Last && number > p.PageNumber {
+		return nil, ErrNoMorePages
+	}
+	filters := make([]Filter, 0, len(p.filters)+1)
+	filters = append(filters, p.filters...)
+	return p.fetch(ctx, append(filters, WithPageNumber(number))...)
+}
+
+// All returns an Iterator over the items on this page and every page after it.
+func (p *Page[T]) All(ctx context.Context) *Iterator[T] {
+	return &Iterator[T]{ctx: ctx, page: p}
+}
+
+// Collect returns the items on this page and the pages after it, stopping
+// once limit items have been gathered. A limit of zero or less collects
+// every item.
+func (p *Page[T]) Collect(ctx context.Context, limit int) ([]T, error) {
+	// Only the items already received are known to exist, the reported total
+	// may be wrong and ignores client-side filters.
+	size := len(p.Items)
+	if limit > 0 && limit < size {
+		size = limit
+	}
+	out := make([]T, 0, size)
+	it := p.All(ctx)
+	for (limit <= 0 || len(out) < limit) && it.Next() {
+		out = append(out, it.Item())
+	}
+	return out, it.Err()
+}
+
+// listPage retrieves a page from a list endpoint.
+func listPage[T any](ctx context.Context, c *client, endpoint string, filters []Filter) (*Page[T], error) {
+	q, err := buildQuery(filters)
+	if err != nil {
+		return nil, err
+	}
+	ep := endpoint
+	if len(q.vals) > 0 {
+		ep = fmt.Sprintf("%s?%s", ep, q.vals.Encode())
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, ep, nil)
+	if err != nil {
+		return nil, err
+	}
+	out := &Page[T]{filters: filters}
+	out.fetch = func(ctx context.Context, filters ...Filter) (*Page[T], error) {
+		return listPage[T](ctx, c, endpoint, filters)
+	}
+	if err := c.doIntoWithAuth(req, out); err != nil {
+		return out, err
+	}
+	out.received = len(out.Items)
+	if len(q.match) > 0 {
+		items := out.Items[:0]
+		for _, item := range out.Items {
+			if q.past(item) {
+				out.Last = true
+				break
+			}
+			if q.matches(item) {
+				items = append(items, item)
+			}
+		}
+		out.Items = items
+	}
+	return out, nil
+}
@@ -0,0 +1,126 @@
+package veem
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultFetchConcurrency is the number of pages fetched at once when
+// FetchOptions does not specify one.
+const DefaultFetchConcurrency = 4
+
+// FetchOptions configures concurrent page fetching.
+type FetchOptions struct {
+	// Concurrency is the maximum number of pages requested at once. Defaults
+	// to DefaultFetchConcurrency.
+	Concurrency int
+	// Interval is the minimum time between starting two requests, to stay
+	// under the API rate limits. Zero means no limit. Rate limited requests
+	// are also retried according to the client's RetryPolicy.
+	Interval time.Duration
+	// MaxPages caps the number of pages fetched in total, including the first
+	// one. Zero means no limit.
+	MaxPages int
+}
+
+// FetchRemaining fetches every page after this one concurrently, using the
+// TotalPages reported by this page, and returns them in order. Results are
+// only consistent if the listing does not change while it is being fetched,
+// so sort by a stable field such as timeCreated for large exports.
+func (p *Page[T]) FetchRemaining(ctx context.Context, opts *FetchOptions) ([]*Page[T], error) {
+	if opts == nil {
+		opts = &FetchOptions{}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultFetchConcurrency
+	}
+	remaining := p.TotalPages - int(p.PageNumber) - 1
+	if opts.MaxPages > 0 && remaining > opts.MaxPages-1 {
+		remaining = opts.MaxPages - 1
+	}
+	if p.Last || remaining <= 0 {
+		return []*Page[T]{}, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var limit <-chan time.Time
+	if opts.Interval > 0 {
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+		limit = ticker.C
+	}
+
+	pages := make([]*Page[T], remaining)
+	numbers := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for i := 0; i < concurrency && i < remaining; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range numbers {
+				page, err := p.page(ctx, p.PageNumber+int32(i)+1)
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				pages[i] = page
+			}
+		}()
+	}
+
+send:
+	for i := 0; i < remaining; i++ {
+		if limit != nil && i > 0 {
+			select {
+			case <-ctx.Done():
+				break send
+			case <-limit:
+			}
+		}
+		select {
+		case <-ctx.Done():
+			break send
+		case numbers <- i:
+		}
+	}
+	close(numbers)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+// CollectConcurrent returns the items on this page followed by those on
+// every page after it, fetched concurrently with FetchRemaining.
+func (p *Page[T]) CollectConcurrent(ctx context.Context, opts *FetchOptions) ([]T, error) {
+	pages, err := p.FetchRemaining(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	size := len(p.Items)
+	for _, page := range pages {
+		size += len(page.Items)
+	}
+	out := make([]T, 0, size)
+	out = append(out, p.Items...)
+	for _, page := range pages {
+		out = append(out, page.Items...)
+	}
+	return out, nil
+}
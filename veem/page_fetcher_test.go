@@ -0,0 +1,34 @@
+package veem
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestCollectConcurrent(t *testing.T) {
+	c := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(r.URL.Query().Get("pageNumber"))
+		// The API overstates the total, the allocation must not follow it.
+		fmt.Fprintf(w, `{"content":[{"id":%d},{"id":%d}],"number":%d,"size":2,"totalPages":3,"totalElements":1000000,"numberOfElements":2,"last":%t}`,
+			2*n, 2*n+1, n, n == 2)
+	})
+	first, err := c.Payments().List(context.Background(), WithPageSize(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := first.CollectConcurrent(context.Background(), &FetchOptions{Concurrency: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 6 || cap(got) != 6 {
+		t.Fatalf("CollectConcurrent = len %d cap %d, want 6 items and capacity", len(got), cap(got))
+	}
+	for i, p := range got {
+		if p.ID != int64(i) {
+			t.Errorf("item %d has ID %d, pages are out of order", i, p.ID)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package veem
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestPageCollect(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   int
+		wantLen int
+		maxCap  int
+	}{
+		{"limited", 2, 2, 2},
+		{"all", 0, 3, 3},
+		{"limit above items", 10, 3, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The API reports totals the page does not hold.
+			for _, total := range []int{1000000, -1} {
+				p := &Page[int]{Items: []int{1, 2, 3}, Last: true, TotalElements: total, NumberOfElements: 3, received: 3}
+				got, err := p.Collect(context.Background(), tt.limit)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(got) != tt.wantLen || cap(got) > tt.maxCap {
+					t.Errorf("Collect(%d) with total %d = len %d cap %d, want len %d cap at most %d", tt.limit, total, len(got), cap(got), tt.wantLen, tt.maxCap)
+				}
+			}
+		})
+	}
+}
+
+func TestPageNextKeepsCallerPageSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		filters  []ContactFilter
+		size     string
+		wantSize string
+	}{
+		{"no size in response", nil, "", ""},
+		{"size above the maximum", nil, `"size":200,`, ""},
+		{"caller size", []ContactFilter{WithPageSize(2)}, `"size":2,`, "2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			sizes := make([]string, 0)
+			c := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				sizes = append(sizes, r.URL.Query().Get("pageSize"))
+				mu.Unlock()
+				number := r.URL.Query().Get("pageNumber")
+				if number == "" {
+					number = "0"
+				}
+				fmt.Fprintf(w, `{"content":[{"id":%s}],%s"number":%s,"last":%t}`, number, tt.size, number, number == "2")
+			})
+			n := 0
+			it := c.Contacts().All(context.Background(), tt.filters...)
+			for it.Next() {
+				n++
+			}
+			if err := it.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if n != 3 {
+				t.Fatalf("got %d contacts, want 3", n)
+			}
+			for i, size := range sizes {
+				if size != tt.wantSize {
+					t.Errorf("request %d pageSize = %q, want %q", i, size, tt.wantSize)
+				}
+			}
+		})
+	}
+}
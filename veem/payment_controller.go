@@ -2,30 +2,32 @@ package veem
 
 import (
 	"bytes"
-	"encoding/json"
-	"errors"
+	"context"
 	"fmt"
 	"net/http"
-	"net/url"
 	"time"
 )
 
 // PaymentController is the interface for interacting with Veem payments.
 type PaymentController interface {
 	// Get a payment by ID
-	Get(id int64) (*Payment, error)
+	Get(ctx context.Context, id int64) (*Payment, error)
 	// Get payments for this account with filters
-	List(filters ...Filter) (*ListPaymentsResponse, error)
+	List(ctx context.Context, filters ...PaymentFilter) (*ListPaymentsResponse, error)
+	// Iterate over every payment for this account matching the filters
+	All(ctx context.Context, filters ...PaymentFilter) *PaymentIterator
 	// Create a new payment
-	Create(payment *DraftPayment) (*Payment, error)
+	Create(ctx context.Context, payment *DraftPayment) (*Payment, error)
+	// Check a payment against the country-currency metadata before creating it
+	Preflight(ctx context.Context, payment *DraftPayment) ([]*FieldError, error)
 	// Create a batch of payments
-	CreateBatch(payments []*DraftPayment, includeItems bool) (*BatchOperation, error)
+	CreateBatch(ctx context.Context, payments []*DraftPayment, includeItems bool) (*BatchOperation, error)
 	// Get the status of a batch operation
-	GetBatch(batchID int64, includeItems bool) (*BatchOperation, error)
+	GetBatch(ctx context.Context, batchID int64, includeItems bool) (*BatchOperation, error)
 	// Approve a payment
-	Approve(id int64) (*Payment, error)
+	Approve(ctx context.Context, id int64) (*Payment, error)
 	// Cancel a payment
-	Cancel(id int64) (*Payment, error)
+	Cancel(ctx context.Context, id int64) (*Payment, error)
 }
 
 type Payment struct {
@@ -45,7 +47,7 @@ type Payment struct {
 	PaymentApprovalRequest *PaymentApprovalRequest `json:"paymentApprovalRequest,omitempty"`
 	PurposeOfPayment       string                  `json:"purposeOfPayment,omitempty"`
 	PushPaymentInfo        *PushPaymentInfo        `json:"pushPaymentInfo,omitempty"`
-	Status                 string                  `json:"status"`
+	Status                 PaymentStatus           `json:"status"`
 	TimeCreated            time.Time               `json:"timeCreated"`
 	TimeUpdated            time.Time               `json:"timeUpdated"`
 }
@@ -91,24 +93,12 @@ type DraftPayment struct {
 
 type paymentControler struct{ *client }
 
-type ListPaymentsResponse struct {
-	Payments []*Payment `json:"content"`
+// ListPaymentsResponse is a page of payments.
+type ListPaymentsResponse = Page[*Payment]
 
-	First            bool  `json:"first"`
-	Last             bool  `json:"last"`
-	NumberOfElements int   `json:"numberOfElements"`
-	TotalElements    int   `json:"totalElements"`
-	PageNumber       int32 `json:"number"`
-	PageSize         int32 `json:"size"`
-	TotalPages       int   `json:"totalPages"`
-
-	controller *paymentControler
-	filters    []Filter
-}
-
-func (p *paymentControler) Get(id int64) (*Payment, error) {
+func (p *paymentControler) Get(ctx context.Context, id int64) (*Payment, error) {
 	ep := fmt.Sprintf("veem/v1.1/payments/%d", id)
-	req, err := p.newRequest(http.MethodGet, ep, nil)
+	req, err := p.newRequest(ctx, http.MethodGet, ep, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -116,40 +106,20 @@ func (p *paymentControler) Get(id int64) (*Payment, error) {
 	return payment, p.doIntoWithAuth(req, payment)
 }
 
-func (p *paymentControler) List(filters ...Filter) (*ListPaymentsResponse, error) {
-	ep := "veem/v1.1/payments"
-	if len(filters) > 0 {
-		vals := &url.Values{}
-		for _, f := range filters {
-			f(vals)
-		}
-		ep = fmt.Sprintf("%s?%s", ep, vals.Encode())
-	} else {
-		filters = make([]Filter, 0)
-	}
-	req, err := p.newRequest(http.MethodGet, ep, nil)
-	if err != nil {
-		return nil, err
-	}
-	out := &ListPaymentsResponse{controller: p, filters: filters}
-	return out, p.doIntoWithAuth(req, out)
+func (p *paymentControler) List(ctx context.Context, filters ...PaymentFilter) (*ListPaymentsResponse, error) {
+	return listPage[*Payment](ctx, p.client, "veem/v1.1/payments", toFilters(filters))
 }
 
-func (g *ListPaymentsResponse) Next() (*ListPaymentsResponse, error) {
-	if g.Last {
-		return nil, errors.New("no more payment pages left")
-	}
-	return g.controller.List(
-		append(g.filters, WithPageNumber(g.PageNumber+1), WithPageSize(g.PageSize))...,
-	)
+func (p *paymentControler) All(ctx context.Context, filters ...PaymentFilter) *PaymentIterator {
+	return &PaymentIterator{ctx: ctx, first: func() (*ListPaymentsResponse, error) { return p.List(ctx, filters...) }}
 }
 
-func (p *paymentControler) Create(payment *DraftPayment) (*Payment, error) {
-	payload, err := json.Marshal(payment)
+func (p *paymentControler) Create(ctx context.Context, payment *DraftPayment) (*Payment, error) {
+	payload, err := marshalRequest(payment)
 	if err != nil {
 		return nil, err
 	}
-	req, err := p.newRequest(http.MethodPost, "veem/v1.1/payments", bytes.NewReader(payload))
+	req, err := p.newCreateRequest(ctx, "veem/v1.1/payments", bytes.NewReader(payload))
 	if err != nil {
 		return nil, err
 	}
@@ -157,13 +127,13 @@ func (p *paymentControler) Create(payment *DraftPayment) (*Payment, error) {
 	return out, p.doIntoWithAuth(req, out)
 }
 
-func (p *paymentControler) CreateBatch(payments []*DraftPayment, includeItems bool) (*BatchOperation, error) {
-	payload, err := json.Marshal(payments)
+func (p *paymentControler) CreateBatch(ctx context.Context, payments []*DraftPayment, includeItems bool) (*BatchOperation, error) {
+	payload, err := marshalRequest(payments)
 	if err != nil {
 		return nil, err
 	}
 	ep := fmt.Sprintf("veem/v1.1/payments/batch?includeItems=%t", includeItems)
-	req, err := p.newRequest(http.MethodPost, ep, bytes.NewReader(payload))
+	req, err := p.newCreateRequest(ctx, ep, bytes.NewReader(payload))
 	if err != nil {
 		return nil, err
 	}
@@ -171,9 +141,9 @@ func (p *paymentControler) CreateBatch(payments []*DraftPayment, includeItems bo
 	return out, p.doIntoWithAuth(req, out)
 }
 
-func (p *paymentControler) GetBatch(batchID int64, includeItems bool) (*BatchOperation, error) {
+func (p *paymentControler) GetBatch(ctx context.Context, batchID int64, includeItems bool) (*BatchOperation, error) {
 	ep := fmt.Sprintf("veem/v1.1/payments/batch/%d?includeItems=%t", batchID, includeItems)
-	req, err := p.newRequest(http.MethodGet, ep, nil)
+	req, err := p.newRequest(ctx, http.MethodGet, ep, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -181,9 +151,9 @@ func (p *paymentControler) GetBatch(batchID int64, includeItems bool) (*BatchOpe
 	return out, p.doIntoWithAuth(req, out)
 }
 
-func (p *paymentControler) Approve(id int64) (*Payment, error) {
+func (p *paymentControler) Approve(ctx context.Context, id int64) (*Payment, error) {
 	ep := fmt.Sprintf("veem/v1.1/payments/%d/approve", id)
-	req, err := p.newRequest(http.MethodPost, ep, nil)
+	req, err := p.newRequest(ctx, http.MethodPost, ep, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -191,9 +161,9 @@ func (p *paymentControler) Approve(id int64) (*Payment, error) {
 	return payment, p.doIntoWithAuth(req, payment)
 }
 
-func (p *paymentControler) Cancel(id int64) (*Payment, error) {
+func (p *paymentControler) Cancel(ctx context.Context, id int64) (*Payment, error) {
 	ep := fmt.Sprintf("veem/v1.1/payments/%d/cancel", id)
-	req, err := p.newRequest(http.MethodPost, ep, nil)
+	req, err := p.newRequest(ctx, http.MethodPost, ep, nil)
 	if err != nil {
 		return nil, err
 	}
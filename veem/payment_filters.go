@@ -0,0 +1,197 @@
+package veem
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The filters below are not supported by the Veem API, so they are applied
+// to each page of payments or invoices after it is retrieved. Pages may
+// therefore hold fewer items than their PageSize. Combine them with
+// server-side filters such as WithStatuses to reduce the number of pages
+// retrieved. When payments are sorted with WithSort on the field of a
+// WithTimeCreatedBetween or WithTimeUpdatedBetween range, paging stops at the
+// first payment past the range, so e.g. last month's payments sorted by
+// timeCreated descending only retrieve the pages back to the start of the
+// month.
+
+// matchPayment adds a client-side check on payments to the query.
+func matchPayment(q *query, match func(p *Payment) bool) {
+	q.match = append(q.match, func(item interface{}) bool {
+		p, ok := item.(*Payment)
+		return ok && match(p)
+	})
+}
+
+// matchInvoice adds a client-side check on invoices to the query.
+func matchInvoice(q *query, match func(inv *Invoice) bool) {
+	q.match = append(q.match, func(item interface{}) bool {
+		inv, ok := item.(*Invoice)
+		return ok && match(inv)
+	})
+}
+
+// transaction holds the fields payments and invoices have in common.
+type transaction struct {
+	timeCreated          time.Time
+	dueDate              time.Time
+	externalInvoiceRefID string
+}
+
+// matchTransaction adds a client-side check on payments or invoices to the
+// query.
+func matchTransaction(q *query, match func(t *transaction) bool) {
+	q.match = append(q.match, func(item interface{}) bool {
+		switch v := item.(type) {
+		case *Payment:
+			return match(&transaction{v.TimeCreated, v.DueDate, v.ExternalInvoiceRefId})
+		case *Invoice:
+			t := &transaction{externalInvoiceRefID: v.ExternalInvoiceRefId}
+			if v.TimeCreated != nil {
+				t.timeCreated = *v.TimeCreated
+			}
+			if v.DueDate != nil {
+				t.dueDate = *v.DueDate
+			}
+			return match(t)
+		}
+		return false
+	})
+}
+
+// sortTime returns the time of a payment listings can be sorted by.
+func sortTime(item interface{}, field SortField) (time.Time, bool) {
+	p, ok := item.(*Payment)
+	if !ok {
+		return time.Time{}, false
+	}
+	switch field {
+	case SortTimeCreated:
+		return p.TimeCreated, true
+	case SortTimeUpdated:
+		return p.TimeUpdated, true
+	}
+	return time.Time{}, false
+}
+
+// timeBetween reports whether t is within from and to, inclusive. A zero
+// bound is open.
+func timeBetween(t, from, to time.Time) bool {
+	return (from.IsZero() || !t.Before(from)) && (to.IsZero() || !t.After(to))
+}
+
+func validateTimeRange(name string, from, to time.Time) error {
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return fmt.Errorf("invalid %s range: %s is before %s", name, to, from)
+	}
+	return nil
+}
+
+// WithTimeCreatedBetween matches payments or invoices created between from
+// and to, inclusive. Either bound may be zero to leave it open.
+func WithTimeCreatedBetween(from, to time.Time) TransactionParam {
+	return func(q *query) error {
+		if err := validateTimeRange("timeCreated", from, to); err != nil {
+			return err
+		}
+		matchTransaction(q, func(t *transaction) bool { return timeBetween(t.timeCreated, from, to) })
+		q.narrow(SortTimeCreated, from, to)
+		return nil
+	}
+}
+
+// WithTimeUpdatedBetween matches payments last updated between from and to,
+// inclusive. Either bound may be zero to leave it open.
+func WithTimeUpdatedBetween(from, to time.Time) PaymentParam {
+	return func(q *query) error {
+		if err := validateTimeRange("timeUpdated", from, to); err != nil {
+			return err
+		}
+		matchPayment(q, func(p *Payment) bool { return timeBetween(p.TimeUpdated, from, to) })
+		q.narrow(SortTimeUpdated, from, to)
+		return nil
+	}
+}
+
+// WithDueDateBetween matches payments or invoices due between from and to,
+// inclusive. Either bound may be zero to leave it open. Items without a due
+// date never match.
+func WithDueDateBetween(from, to time.Time) TransactionParam {
+	return func(q *query) error {
+		if err := validateTimeRange("dueDate", from, to); err != nil {
+			return err
+		}
+		matchTransaction(q, func(t *transaction) bool {
+			return !t.dueDate.IsZero() && timeBetween(t.dueDate, from, to)
+		})
+		return nil
+	}
+}
+
+// WithAmountBetween matches payments whose payee amount is between min and
+// max, inclusive, regardless of currency. Combine it with WithCurrencies to
+// compare amounts in a single currency.
+func WithAmountBetween(min, max Decimal) PaymentParam {
+	return func(q *query) error {
+		if max.Cmp(min) < 0 {
+			return fmt.Errorf("invalid amount range: %s is less than %s", max, min)
+		}
+		matchPayment(q, func(p *Payment) bool {
+			return p.PayeeAmount != nil && p.PayeeAmount.Number.Cmp(min) >= 0 && p.PayeeAmount.Number.Cmp(max) <= 0
+		})
+		return nil
+	}
+}
+
+// WithCurrencies matches payments whose payee amount is in one of the given
+// currencies.
+func WithCurrencies(currencies ...CurrencyCode) PaymentParam {
+	return func(q *query) error {
+		if len(currencies) == 0 {
+			return errors.New("at least one currency is required")
+		}
+		matchPayment(q, func(p *Payment) bool {
+			if p.PayeeAmount == nil {
+				return false
+			}
+			for _, c := range currencies {
+				if strings.EqualFold(string(p.PayeeAmount.Currency), string(c)) {
+					return true
+				}
+			}
+			return false
+		})
+		return nil
+	}
+}
+
+// WithPayeeEmail matches payments to the payee with the given email address.
+func WithPayeeEmail(email string) PaymentParam {
+	return func(q *query) error {
+		matchPayment(q, func(p *Payment) bool {
+			return p.Payee != nil && strings.EqualFold(p.Payee.Email, email)
+		})
+		return nil
+	}
+}
+
+// WithExternalInvoiceRefID matches payments or invoices with the given
+// external invoice reference.
+func WithExternalInvoiceRefID(ref string) TransactionParam {
+	return func(q *query) error {
+		matchTransaction(q, func(t *transaction) bool { return t.externalInvoiceRefID == ref })
+		return nil
+	}
+}
+
+// WithPayerEmail matches invoices to the payer with the given email address.
+func WithPayerEmail(email string) InvoiceParam {
+	return func(q *query) error {
+		matchInvoice(q, func(inv *Invoice) bool {
+			return inv.Payer != nil && strings.EqualFold(inv.Payer.Email, email)
+		})
+		return nil
+	}
+}
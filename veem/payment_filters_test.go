@@ -0,0 +1,134 @@
+package veem
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPaymentFilters(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2021, 3, d, 0, 0, 0, 0, time.UTC) }
+	payments := []*Payment{
+		{ID: 1, TimeCreated: day(1), TimeUpdated: day(2), DueDate: day(10), ExternalInvoiceRefId: "INV-1",
+			Payee: &Entity{Email: "a@example.com"}, PayeeAmount: &Amount{Number: MustParseDecimal("50"), Currency: "EUR"}},
+		{ID: 2, TimeCreated: day(5), TimeUpdated: day(6), ExternalInvoiceRefId: "INV-2",
+			Payee: &Entity{Email: "b@example.com"}, PayeeAmount: &Amount{Number: MustParseDecimal("15000"), Currency: "EUR"}},
+		{ID: 3, TimeCreated: day(9), TimeUpdated: day(20), DueDate: day(30),
+			Payee: &Entity{Email: "A@example.com"}, PayeeAmount: &Amount{Number: MustParseDecimal("15000"), Currency: "USD"}},
+		{ID: 4, TimeCreated: day(12)},
+	}
+	tests := []struct {
+		name    string
+		filters []PaymentFilter
+		want    []int64
+	}{
+		{"created", []PaymentFilter{WithTimeCreatedBetween(day(2), day(9))}, []int64{2, 3}},
+		{"created open start", []PaymentFilter{WithTimeCreatedBetween(time.Time{}, day(5))}, []int64{1, 2}},
+		{"updated", []PaymentFilter{WithTimeUpdatedBetween(day(6), time.Time{})}, []int64{2, 3}},
+		{"due date skips missing", []PaymentFilter{WithDueDateBetween(day(1), day(31))}, []int64{1, 3}},
+		{"amount", []PaymentFilter{WithAmountBetween(MustParseDecimal("10000"), MustParseDecimal("20000"))}, []int64{2, 3}},
+		{"currency", []PaymentFilter{WithCurrencies("eur")}, []int64{1, 2}},
+		{"last month's EUR over 10k", []PaymentFilter{
+			WithTimeCreatedBetween(day(1), day(31)), WithCurrencies("EUR"), WithAmountBetween(MustParseDecimal("10000"), MustParseDecimal("1e9")),
+		}, []int64{2}},
+		{"payee email", []PaymentFilter{WithPayeeEmail("a@example.com")}, []int64{1, 3}},
+		{"external ref", []PaymentFilter{WithExternalInvoiceRefID("INV-2")}, []int64{2}},
+	}
+	for _, tt := range tests {
+		q, err := buildQuery(toFilters(tt.filters))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(q.vals) != 0 {
+			t.Errorf("%s: sent query parameters %v", tt.name, q.vals)
+		}
+		got := make([]int64, 0)
+		for _, p := range payments {
+			if q.matches(p) {
+				got = append(got, p.ID)
+			}
+		}
+		if !equalIDs(got, tt.want) {
+			t.Errorf("%s: matched %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPaymentFilterValidation(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name   string
+		filter PaymentFilter
+	}{
+		{"created range reversed", WithTimeCreatedBetween(now, now.Add(-time.Hour))},
+		{"updated range reversed", WithTimeUpdatedBetween(now, now.Add(-time.Hour))},
+		{"due date range reversed", WithDueDateBetween(now, now.Add(-time.Hour))},
+		{"amount range reversed", WithAmountBetween(MustParseDecimal("2"), MustParseDecimal("1"))},
+		{"no currencies", WithCurrencies()},
+	}
+	for _, tt := range tests {
+		if _, err := buildQuery([]Filter{tt.filter}); err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+	}
+}
+
+func TestSortedListingStopsPastRange(t *testing.T) {
+	start := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		filters   []PaymentFilter
+		want      []int64
+		wantPages int
+	}{
+		// Pages hold payments created 20, 19, ... days after start, newest first.
+		{"descending stops before the start", []PaymentFilter{
+			WithSort(SortTimeCreated, SortDescending), WithTimeCreatedBetween(start.AddDate(0, 0, 15), time.Time{}),
+		}, []int64{20, 19, 18, 17, 16, 15}, 3},
+		{"ascending field does not stop", []PaymentFilter{
+			WithSort(SortTimeCreated, SortAscending), WithTimeCreatedBetween(start.AddDate(0, 0, 15), time.Time{}),
+		}, []int64{20, 19, 18, 17, 16, 15}, 7},
+		{"other sort field does not stop", []PaymentFilter{
+			WithSort(SortTimeUpdated, SortDescending), WithTimeCreatedBetween(start.AddDate(0, 0, 15), time.Time{}),
+		}, []int64{20, 19, 18, 17, 16, 15}, 7},
+		{"unsorted does not stop", []PaymentFilter{
+			WithTimeCreatedBetween(start.AddDate(0, 0, 15), time.Time{}),
+		}, []int64{20, 19, 18, 17, 16, 15}, 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			pages := 0
+			c := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				pages++
+				mu.Unlock()
+				number, _ := strconv.Atoi(r.URL.Query().Get("pageNumber"))
+				items := make([]*Payment, 0, 3)
+				for i := 0; i < 3; i++ {
+					id := 20 - number*3 - i
+					if id < 0 {
+						break
+					}
+					items = append(items, &Payment{ID: int64(id), TimeCreated: start.AddDate(0, 0, id)})
+				}
+				json.NewEncoder(w).Encode(&Page[*Payment]{Items: items, PageNumber: int32(number), Last: number == 6})
+			})
+			got := make([]int64, 0)
+			it := c.Payments().All(context.Background(), tt.filters...)
+			for it.Next() {
+				got = append(got, it.Item().ID)
+			}
+			if err := it.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if !equalIDs(got, tt.want) || pages != tt.wantPages {
+				t.Errorf("got %v from %d pages, want %v from %d", got, pages, tt.want, tt.wantPages)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package veem
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Codes of the problems reported by Preflight and ValidateBankAccount.
+// ProblemUnknown is reported for bank fields the metadata lists that this
+// package does not recognise, and so cannot check.
+const (
+	ProblemRequired    = "required"
+	ProblemInvalid     = "invalid"
+	ProblemUnsupported = "unsupported"
+	ProblemExtraneous  = "extraneous"
+	ProblemUnknown     = "unknown"
+)
+
+// preflightRequest holds the parts of a payment or invoice checked before it
+// is created.
+type preflightRequest struct {
+	// The JSON name of the counterparty, "payee" or "payer".
+	party       string
+	entity      *Entity
+	amount      *Amount
+	attachments []*Attachment
+	purpose     string
+	// Whether the counterparty receives the amount, rather than sends it.
+	receiving bool
+}
+
+func (p *paymentControler) Preflight(ctx context.Context, payment *DraftPayment) ([]*FieldError, error) {
+	meta, err := p.metadata.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return preflight(meta, &preflightRequest{
+		party:       "payee",
+		entity:      payment.Payee,
+		amount:      payment.Amount,
+		attachments: payment.Attachments,
+		purpose:     payment.PurposeOfPayment,
+		receiving:   true,
+	}), nil
+}
+
+func (i *invoiceController) Preflight(ctx context.Context, inv *Invoice) ([]*FieldError, error) {
+	meta, err := i.metadata.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return preflight(meta, &preflightRequest{
+		party:       "payer",
+		entity:      inv.Payer,
+		amount:      inv.Amount,
+		attachments: inv.Attachments,
+		purpose:     inv.PurposeOfPayment,
+	}), nil
+}
+
+// preflight checks the request against the country-currency metadata and
+// returns every problem found.
+func preflight(metadata *Metadata, req *preflightRequest) []*FieldError {
+	problems := make([]*FieldError, 0)
+	report := func(field, code, format string, args ...interface{}) {
+		problems = append(problems, &FieldError{Field: field, Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if req.amount == nil {
+		report("amount", ProblemRequired, "an amount is required")
+	} else {
+		switch {
+		case req.amount.Currency == "":
+			report("amount.currency", ProblemRequired, "a currency is required")
+		case !req.amount.Currency.Valid():
+			report("amount.currency", ProblemInvalid, "%q is not an ISO 4217 currency code", string(req.amount.Currency))
+		case req.amount.Number.Scale() > req.amount.Currency.MinorUnits():
+			report("amount.number", ProblemInvalid, "%s amounts have at most %d decimal places", req.amount.Currency, req.amount.Currency.MinorUnits())
+		}
+		if req.amount.Number.Sign() <= 0 {
+			report("amount.number", ProblemInvalid, "the amount must be greater than zero")
+		}
+	}
+
+	if req.entity == nil {
+		report(req.party, ProblemRequired, "a %s is required", req.party)
+		return problems
+	}
+	country := req.entity.CountryCode
+	field := req.party + ".countryCode"
+	if country == "" {
+		report(field, ProblemRequired, "the %s country is required", req.party)
+		return problems
+	}
+	if !country.Valid() {
+		report(field, ProblemInvalid, "%q is not an ISO 3166 country code", string(country))
+		return problems
+	}
+	meta, ok := metadata.Country(country)
+	if !ok {
+		report(field, ProblemUnsupported, "Veem does not support %s", country.Name())
+		return problems
+	}
+
+	if req.amount != nil && req.amount.Currency.Valid() {
+		if req.receiving && !meta.CanReceive(req.amount.Currency) {
+			report("amount.currency", ProblemUnsupported, "%ss in %s cannot receive %s, supported currencies are %s",
+				req.party, country.Name(), req.amount.Currency, joinCurrencies(meta.ReceivingCurrencies))
+		}
+		if !req.receiving && !meta.CanSend(req.amount.Currency) {
+			report("amount.currency", ProblemUnsupported, "%ss in %s cannot send %s, supported currencies are %s",
+				req.party, country.Name(), req.amount.Currency, joinCurrencies(meta.SendingCurrencies))
+		}
+	}
+
+	if meta.InvoiceAttachmentRequired && !hasAttachment(req.attachments, ExternalInvoiceAttachment) {
+		report("attachments", ProblemRequired, "an %s attachment is required for %ss in %s", ExternalInvoiceAttachment, req.party, country.Name())
+	}
+
+	switch {
+	case req.purpose == "" && meta.PurposeOfPaymentRequired:
+		report("purposeOfPayment", ProblemRequired, "a purpose of payment is required for %ss in %s", req.party, country.Name())
+	case req.purpose != "" && len(meta.PurposeOfPaymentInfo) > 0 && !hasPurpose(meta.PurposeOfPaymentInfo, req.purpose):
+		report("purposeOfPayment", ProblemInvalid, "%q is not a purpose of payment code for %s", req.purpose, country.Name())
+	}
+	return problems
+}
+
+func hasAttachment(attachments []*Attachment, t AttachmentType) bool {
+	for _, a := range attachments {
+		if a != nil && a.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPurpose(purposes []*PaymentPurpose, code string) bool {
+	for _, p := range purposes {
+		if strings.EqualFold(p.PurposeCode, code) {
+			return true
+		}
+	}
+	return false
+}
+
+func joinCurrencies(currencies []CurrencyCode) string {
+	out := make([]string, len(currencies))
+	for i, c := range currencies {
+		out[i] = string(c)
+	}
+	return strings.Join(out, ", ")
+}
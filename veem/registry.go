@@ -0,0 +1,228 @@
+package veem
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// CurrencyCode is an ISO 4217 currency code, e.g. "USD".
+type CurrencyCode string
+
+type currencyInfo struct {
+	name       string
+	minorUnits int32
+}
+
+// ParseCurrencyCode returns the CurrencyCode for s, in any case, or an error
+// if it is not a known ISO 4217 code.
+func ParseCurrencyCode(s string) (CurrencyCode, error) {
+	c := CurrencyCode(strings.ToUpper(strings.TrimSpace(s)))
+	if !c.Valid() {
+		return "", fmt.Errorf("unknown currency code %q", s)
+	}
+	return c, nil
+}
+
+// Valid reports whether c is a known ISO 4217 code, in any case.
+func (c CurrencyCode) Valid() bool {
+	_, ok := currencies[c.upper()]
+	return ok
+}
+
+// Name returns the name of the currency, or "" if it is unknown.
+func (c CurrencyCode) Name() string { return currencies[c.upper()].name }
+
+func (c CurrencyCode) upper() CurrencyCode { return CurrencyCode(strings.ToUpper(string(c))) }
+
+// MarshalJSON implements json.Marshaler, encoding the code in upper case as
+// Veem expects.
+func (c CurrencyCode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(c.upper()))
+}
+
+// MinorUnits returns the number of digits after the decimal point used by
+// the currency, e.g. 0 for JPY, 2 for USD and 3 for BHD. Unknown currencies
+// use 2.
+func (c CurrencyCode) MinorUnits() int32 {
+	if info, ok := currencies[c.upper()]; ok {
+		return info.minorUnits
+	}
+	return 2
+}
+
+// Currencies returns every known currency code, sorted.
+func Currencies() []CurrencyCode {
+	out := make([]CurrencyCode, 0, len(currencies))
+	for c := range currencies {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// CountryCode is an ISO 3166-1 alpha-2 country code, e.g. "US".
+type CountryCode string
+
+// ParseCountryCode returns the CountryCode for s, in any case, or an error
+// if it is not a known ISO 3166-1 alpha-2 code.
+func ParseCountryCode(s string) (CountryCode, error) {
+	c := CountryCode(strings.ToUpper(strings.TrimSpace(s)))
+	if !c.Valid() {
+		return "", fmt.Errorf("unknown country code %q", s)
+	}
+	return c, nil
+}
+
+// Valid reports whether c is a known ISO 3166-1 alpha-2 code, in any case.
+func (c CountryCode) Valid() bool {
+	_, ok := countries[c.upper()]
+	return ok
+}
+
+// Name returns the name of the country, or "" if it is unknown.
+func (c CountryCode) Name() string { return countries[c.upper()] }
+
+func (c CountryCode) upper() CountryCode { return CountryCode(strings.ToUpper(string(c))) }
+
+// MarshalJSON implements json.Marshaler, encoding the code in upper case as
+// Veem expects.
+func (c CountryCode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(c.upper()))
+}
+
+// Countries returns every known country code, sorted.
+func Countries() []CountryCode {
+	out := make([]CountryCode, 0, len(countries))
+	for c := range countries {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// marshalRequest encodes a request payload, rejecting unknown currency and
+// country codes so they are caught before being sent to Veem. Empty codes
+// are allowed, and codes are sent in upper case whatever case they were given
+// in. Responses are not checked, as Veem may use codes newer than the
+// registry.
+func marshalRequest(v interface{}) ([]byte, error) {
+	if err := checkCodes(reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+var (
+	countryCodeType  = reflect.TypeOf(CountryCode(""))
+	currencyCodeType = reflect.TypeOf(CurrencyCode(""))
+)
+
+// checkCodes walks v and returns an error for the first unknown code.
+func checkCodes(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return checkCodes(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				if err := checkCodes(v.Field(i)); err != nil {
+					return err
+				}
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := checkCodes(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.String:
+		switch v.Type() {
+		case countryCodeType:
+			if c := CountryCode(v.String()); c != "" && !c.Valid() {
+				return fmt.Errorf("unknown country code %q", string(c))
+			}
+		case currencyCodeType:
+			if c := CurrencyCode(v.String()); c != "" && !c.Valid() {
+				return fmt.Errorf("unknown currency code %q", string(c))
+			}
+		}
+	}
+	return nil
+}
+
+// UnsupportedCurrencyError is returned when Veem does not support paying
+// or receiving a currency in a country.
+type UnsupportedCurrencyError struct {
+	Country  CountryCode
+	Currency CurrencyCode
+	// Whether the currency was checked for receiving rather than sending.
+	Receiving bool
+}
+
+// Error implements the error interface.
+func (u *UnsupportedCurrencyError) Error() string {
+	action := "sending"
+	if u.Receiving {
+		action = "receiving"
+	}
+	return fmt.Sprintf("veem does not support %s %s in %s", action, u.Currency, u.Country)
+}
+
+// CanReceive reports whether payees in the country can receive the currency.
+func (m *CountryCurrentMap) CanReceive(currency CurrencyCode) bool {
+	return containsCurrency(m.ReceivingCurrencies, currency)
+}
+
+// CanSend reports whether payers in the country can send the currency.
+func (m *CountryCurrentMap) CanSend(currency CurrencyCode) bool {
+	return containsCurrency(m.SendingCurrencies, currency)
+}
+
+func containsCurrency(list []CurrencyCode, currency CurrencyCode) bool {
+	for _, c := range list {
+		if strings.EqualFold(string(c), string(currency)) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckCurrency returns an *UnsupportedCurrencyError unless the country is
+// in maps and supports receiving, or sending, the currency.
+func CheckCurrency(maps []*CountryCurrentMap, country CountryCode, currency CurrencyCode, receiving bool) error {
+	for _, m := range maps {
+		if !strings.EqualFold(string(m.Country), string(country)) {
+			continue
+		}
+		if (receiving && m.CanReceive(currency)) || (!receiving && m.CanSend(currency)) {
+			return nil
+		}
+		break
+	}
+	return &UnsupportedCurrencyError{Country: country, Currency: currency, Receiving: receiving}
+}
+
+// CheckCurrency validates the codes against the registry and then checks the
+// live country-currency map to see whether Veem supports the currency in
+// the country.
+func (m *metaController) CheckCurrency(ctx context.Context, country CountryCode, currency CurrencyCode, receiving bool) error {
+	if !country.Valid() {
+		return fmt.Errorf("unknown country code %q", string(country))
+	}
+	if !currency.Valid() {
+		return fmt.Errorf("unknown currency code %q", string(currency))
+	}
+	maps, err := m.CountryCurrencyMap(ctx, false)
+	if err != nil {
+		return err
+	}
+	return CheckCurrency(maps, country, currency, receiving)
+}
@@ -0,0 +1,430 @@
+package veem
+
+// The tables below follow ISO 4217 and ISO 3166-1. Withdrawn currencies are
+// kept while Veem may still report them.
+
+// currencies holds the name and minor units of every ISO 4217 currency used
+// for payments.
+var currencies = map[CurrencyCode]currencyInfo{
+	"AED": {"UAE Dirham", 2},
+	"AFN": {"Afghani", 2},
+	"ALL": {"Lek", 2},
+	"AMD": {"Armenian Dram", 2},
+	"ANG": {"Netherlands Antillean Guilder", 2},
+	"AOA": {"Kwanza", 2},
+	"ARS": {"Argentine Peso", 2},
+	"AUD": {"Australian Dollar", 2},
+	"AWG": {"Aruban Florin", 2},
+	"AZN": {"Azerbaijan Manat", 2},
+	"BAM": {"Convertible Mark", 2},
+	"BBD": {"Barbados Dollar", 2},
+	"BDT": {"Taka", 2},
+	"BGN": {"Bulgarian Lev", 2},
+	"BHD": {"Bahraini Dinar", 3},
+	"BIF": {"Burundi Franc", 0},
+	"BMD": {"Bermudian Dollar", 2},
+	"BND": {"Brunei Dollar", 2},
+	"BOB": {"Boliviano", 2},
+	"BOV": {"Mvdol", 2},
+	"BRL": {"Brazilian Real", 2},
+	"BSD": {"Bahamian Dollar", 2},
+	"BTN": {"Ngultrum", 2},
+	"BWP": {"Pula", 2},
+	"BYN": {"Belarusian Ruble", 2},
+	"BZD": {"Belize Dollar", 2},
+	"CAD": {"Canadian Dollar", 2},
+	"CDF": {"Congolese Franc", 2},
+	"CHE": {"WIR Euro", 2},
+	"CHF": {"Swiss Franc", 2},
+	"CHW": {"WIR Franc", 2},
+	"CLF": {"Unidad de Fomento", 4},
+	"CLP": {"Chilean Peso", 0},
+	"CNY": {"Yuan Renminbi", 2},
+	"COP": {"Colombian Peso", 2},
+	"COU": {"Unidad de Valor Real", 2},
+	"CRC": {"Costa Rican Colon", 2},
+	"CUC": {"Peso Convertible", 2},
+	"CUP": {"Cuban Peso", 2},
+	"CVE": {"Cabo Verde Escudo", 2},
+	"CZK": {"Czech Koruna", 2},
+	"DJF": {"Djibouti Franc", 0},
+	"DKK": {"Danish Krone", 2},
+	"DOP": {"Dominican Peso", 2},
+	"DZD": {"Algerian Dinar", 2},
+	"EGP": {"Egyptian Pound", 2},
+	"ERN": {"Nakfa", 2},
+	"ETB": {"Ethiopian Birr", 2},
+	"EUR": {"Euro", 2},
+	"FJD": {"Fiji Dollar", 2},
+	"FKP": {"Falkland Islands Pound", 2},
+	"GBP": {"Pound Sterling", 2},
+	"GEL": {"Lari", 2},
+	"GHS": {"Ghana Cedi", 2},
+	"GIP": {"Gibraltar Pound", 2},
+	"GMD": {"Dalasi", 2},
+	"GNF": {"Guinean Franc", 0},
+	"GTQ": {"Quetzal", 2},
+	"GYD": {"Guyana Dollar", 2},
+	"HKD": {"Hong Kong Dollar", 2},
+	"HNL": {"Lempira", 2},
+	"HRK": {"Kuna", 2},
+	"HTG": {"Gourde", 2},
+	"HUF": {"Forint", 2},
+	"IDR": {"Rupiah", 2},
+	"ILS": {"New Israeli Sheqel", 2},
+	"INR": {"Indian Rupee", 2},
+	"IQD": {"Iraqi Dinar", 3},
+	"IRR": {"Iranian Rial", 2},
+	"ISK": {"Iceland Krona", 0},
+	"JMD": {"Jamaican Dollar", 2},
+	"JOD": {"Jordanian Dinar", 3},
+	"JPY": {"Yen", 0},
+	"KES": {"Kenyan Shilling", 2},
+	"KGS": {"Som", 2},
+	"KHR": {"Riel", 2},
+	"KMF": {"Comorian Franc", 0},
+	"KPW": {"North Korean Won", 2},
+	"KRW": {"Won", 0},
+	"KWD": {"Kuwaiti Dinar", 3},
+	"KYD": {"Cayman Islands Dollar", 2},
+	"KZT": {"Tenge", 2},
+	"LAK": {"Lao Kip", 2},
+	"LBP": {"Lebanese Pound", 2},
+	"LKR": {"Sri Lanka Rupee", 2},
+	"LRD": {"Liberian Dollar", 2},
+	"LSL": {"Loti", 2},
+	"LYD": {"Libyan Dinar", 3},
+	"MAD": {"Moroccan Dirham", 2},
+	"MDL": {"Moldovan Leu", 2},
+	"MGA": {"Malagasy Ariary", 2},
+	"MKD": {"Denar", 2},
+	"MMK": {"Kyat", 2},
+	"MNT": {"Tugrik", 2},
+	"MOP": {"Pataca", 2},
+	"MRU": {"Ouguiya", 2},
+	"MUR": {"Mauritius Rupee", 2},
+	"MVR": {"Rufiyaa", 2},
+	"MWK": {"Malawi Kwacha", 2},
+	"MXN": {"Mexican Peso", 2},
+	"MXV": {"Mexican Unidad de Inversion (UDI)", 2},
+	"MYR": {"Malaysian Ringgit", 2},
+	"MZN": {"Mozambique Metical", 2},
+	"NAD": {"Namibia Dollar", 2},
+	"NGN": {"Naira", 2},
+	"NIO": {"Cordoba Oro", 2},
+	"NOK": {"Norwegian Krone", 2},
+	"NPR": {"Nepalese Rupee", 2},
+	"NZD": {"New Zealand Dollar", 2},
+	"OMR": {"Rial Omani", 3},
+	"PAB": {"Balboa", 2},
+	"PEN": {"Sol", 2},
+	"PGK": {"Kina", 2},
+	"PHP": {"Philippine Peso", 2},
+	"PKR": {"Pakistan Rupee", 2},
+	"PLN": {"Zloty", 2},
+	"PYG": {"Guarani", 0},
+	"QAR": {"Qatari Rial", 2},
+	"RON": {"Romanian Leu", 2},
+	"RSD": {"Serbian Dinar", 2},
+	"RUB": {"Russian Ruble", 2},
+	"RWF": {"Rwanda Franc", 0},
+	"SAR": {"Saudi Riyal", 2},
+	"SBD": {"Solomon Islands Dollar", 2},
+	"SCR": {"Seychelles Rupee", 2},
+	"SDG": {"Sudanese Pound", 2},
+	"SEK": {"Swedish Krona", 2},
+	"SGD": {"Singapore Dollar", 2},
+	"SHP": {"Saint Helena Pound", 2},
+	"SLE": {"Leone", 2},
+	"SLL": {"Leone", 2},
+	"SOS": {"Somali Shilling", 2},
+	"SRD": {"Surinam Dollar", 2},
+	"SSP": {"South Sudanese Pound", 2},
+	"STN": {"Dobra", 2},
+	"SVC": {"El Salvador Colon", 2},
+	"SYP": {"Syrian Pound", 2},
+	"SZL": {"Lilangeni", 2},
+	"THB": {"Baht", 2},
+	"TJS": {"Somoni", 2},
+	"TMT": {"Turkmenistan New Manat", 2},
+	"TND": {"Tunisian Dinar", 3},
+	"TOP": {"Pa’anga", 2},
+	"TRY": {"Turkish Lira", 2},
+	"TTD": {"Trinidad and Tobago Dollar", 2},
+	"TWD": {"New Taiwan Dollar", 2},
+	"TZS": {"Tanzanian Shilling", 2},
+	"UAH": {"Hryvnia", 2},
+	"UGX": {"Uganda Shilling", 0},
+	"USD": {"US Dollar", 2},
+	"USN": {"US Dollar (Next day)", 2},
+	"UYI": {"Uruguay Peso en Unidades Indexadas (UI)", 0},
+	"UYU": {"Peso Uruguayo", 2},
+	"UYW": {"Unidad Previsional", 4},
+	"UZS": {"Uzbekistan Sum", 2},
+	"VED": {"Bolívar Soberano", 2},
+	"VES": {"Bolívar Soberano", 2},
+	"VND": {"Dong", 0},
+	"VUV": {"Vatu", 0},
+	"WST": {"Tala", 2},
+	"XAF": {"CFA Franc BEAC", 0},
+	"XCD": {"East Caribbean Dollar", 2},
+	"XOF": {"CFA Franc BCEAO", 0},
+	"XPF": {"CFP Franc", 0},
+	"YER": {"Yemeni Rial", 2},
+	"ZAR": {"Rand", 2},
+	"ZMW": {"Zambian Kwacha", 2},
+	"ZWL": {"Zimbabwe Dollar", 2},
+}
+
+// countries holds the name of every ISO 3166-1 country.
+var countries = map[CountryCode]string{
+	"AD": "Andorra",
+	"AE": "United Arab Emirates",
+	"AF": "Afghanistan",
+	"AG": "Antigua and Barbuda",
+	"AI": "Anguilla",
+	"AL": "Albania",
+	"AM": "Armenia",
+	"AO": "Angola",
+	"AQ": "Antarctica",
+	"AR": "Argentina",
+	"AS": "American Samoa",
+	"AT": "Austria",
+	"AU": "Australia",
+	"AW": "Aruba",
+	"AX": "Åland Islands",
+	"AZ": "Azerbaijan",
+	"BA": "Bosnia and Herzegovina",
+	"BB": "Barbados",
+	"BD": "Bangladesh",
+	"BE": "Belgium",
+	"BF": "Burkina Faso",
+	"BG": "Bulgaria",
+	"BH": "Bahrain",
+	"BI": "Burundi",
+	"BJ": "Benin",
+	"BL": "Saint Barthélemy",
+	"BM": "Bermuda",
+	"BN": "Brunei Darussalam",
+	"BO": "Bolivia",
+	"BQ": "Bonaire, Sint Eustatius and Saba",
+	"BR": "Brazil",
+	"BS": "Bahamas",
+	"BT": "Bhutan",
+	"BV": "Bouvet Island",
+	"BW": "Botswana",
+	"BY": "Belarus",
+	"BZ": "Belize",
+	"CA": "Canada",
+	"CC": "Cocos (Keeling) Islands",
+	"CD": "Congo, The Democratic Republic of the",
+	"CF": "Central African Republic",
+	"CG": "Congo",
+	"CH": "Switzerland",
+	"CI": "Côte d'Ivoire",
+	"CK": "Cook Islands",
+	"CL": "Chile",
+	"CM": "Cameroon",
+	"CN": "China",
+	"CO": "Colombia",
+	"CR": "Costa Rica",
+	"CU": "Cuba",
+	"CV": "Cabo Verde",
+	"CW": "Curaçao",
+	"CX": "Christmas Island",
+	"CY": "Cyprus",
+	"CZ": "Czechia",
+	"DE": "Germany",
+	"DJ": "Djibouti",
+	"DK": "Denmark",
+	"DM": "Dominica",
+	"DO": "Dominican Republic",
+	"DZ": "Algeria",
+	"EC": "Ecuador",
+	"EE": "Estonia",
+	"EG": "Egypt",
+	"EH": "Western Sahara",
+	"ER": "Eritrea",
+	"ES": "Spain",
+	"ET": "Ethiopia",
+	"FI": "Finland",
+	"FJ": "Fiji",
+	"FK": "Falkland Islands (Malvinas)",
+	"FM": "Micronesia, Federated States of",
+	"FO": "Faroe Islands",
+	"FR": "France",
+	"GA": "Gabon",
+	"GB": "United Kingdom",
+	"GD": "Grenada",
+	"GE": "Georgia",
+	"GF": "French Guiana",
+	"GG": "Guernsey",
+	"GH": "Ghana",
+	"GI": "Gibraltar",
+	"GL": "Greenland",
+	"GM": "Gambia",
+	"GN": "Guinea",
+	"GP": "Guadeloupe",
+	"GQ": "Equatorial Guinea",
+	"GR": "Greece",
+	"GS": "South Georgia and the South Sandwich Islands",
+	"GT": "Guatemala",
+	"GU": "Guam",
+	"GW": "Guinea-Bissau",
+	"GY": "Guyana",
+	"HK": "Hong Kong",
+	"HM": "Heard Island and McDonald Islands",
+	"HN": "Honduras",
+	"HR": "Croatia",
+	"HT": "Haiti",
+	"HU": "Hungary",
+	"ID": "Indonesia",
+	"IE": "Ireland",
+	"IL": "Israel",
+	"IM": "Isle of Man",
+	"IN": "India",
+	"IO": "British Indian Ocean Territory",
+	"IQ": "Iraq",
+	"IR": "Iran",
+	"IS": "Iceland",
+	"IT": "Italy",
+	"JE": "Jersey",
+	"JM": "Jamaica",
+	"JO": "Jordan",
+	"JP": "Japan",
+	"KE": "Kenya",
+	"KG": "Kyrgyzstan",
+	"KH": "Cambodia",
+	"KI": "Kiribati",
+	"KM": "Comoros",
+	"KN": "Saint Kitts and Nevis",
+	"KP": "North Korea",
+	"KR": "South Korea",
+	"KW": "Kuwait",
+	"KY": "Cayman Islands",
+	"KZ": "Kazakhstan",
+	"LA": "Laos",
+	"LB": "Lebanon",
+	"LC": "Saint Lucia",
+	"LI": "Liechtenstein",
+	"LK": "Sri Lanka",
+	"LR": "Liberia",
+	"LS": "Lesotho",
+	"LT": "Lithuania",
+	"LU": "Luxembourg",
+	"LV": "Latvia",
+	"LY": "Libya",
+	"MA": "Morocco",
+	"MC": "Monaco",
+	"MD": "Moldova",
+	"ME": "Montenegro",
+	"MF": "Saint Martin (French part)",
+	"MG": "Madagascar",
+	"MH": "Marshall Islands",
+	"MK": "North Macedonia",
+	"ML": "Mali",
+	"MM": "Myanmar",
+	"MN": "Mongolia",
+	"MO": "Macao",
+	"MP": "Northern Mariana Islands",
+	"MQ": "Martinique",
+	"MR": "Mauritania",
+	"MS": "Montserrat",
+	"MT": "Malta",
+	"MU": "Mauritius",
+	"MV": "Maldives",
+	"MW": "Malawi",
+	"MX": "Mexico",
+	"MY": "Malaysia",
+	"MZ": "Mozambique",
+	"NA": "Namibia",
+	"NC": "New Caledonia",
+	"NE": "Niger",
+	"NF": "Norfolk Island",
+	"NG": "Nigeria",
+	"NI": "Nicaragua",
+	"NL": "Netherlands",
+	"NO": "Norway",
+	"NP": "Nepal",
+	"NR": "Nauru",
+	"NU": "Niue",
+	"NZ": "New Zealand",
+	"OM": "Oman",
+	"PA": "Panama",
+	"PE": "Peru",
+	"PF": "French Polynesia",
+	"PG": "Papua New Guinea",
+	"PH": "Philippines",
+	"PK": "Pakistan",
+	"PL": "Poland",
+	"PM": "Saint Pierre and Miquelon",
+	"PN": "Pitcairn",
+	"PR": "Puerto Rico",
+	"PS": "Palestine, State of",
+	"PT": "Portugal",
+	"PW": "Palau",
+	"PY": "Paraguay",
+	"QA": "Qatar",
+	"RE": "Réunion",
+	"RO": "Romania",
+	"RS": "Serbia",
+	"RU": "Russian Federation",
+	"RW": "Rwanda",
+	"SA": "Saudi Arabia",
+	"SB": "Solomon Islands",
+	"SC": "Seychelles",
+	"SD": "Sudan",
+	"SE": "Sweden",
+	"SG": "Singapore",
+	"SH": "Saint Helena, Ascension and Tristan da Cunha",
+	"SI": "Slovenia",
+	"SJ": "Svalbard and Jan Mayen",
+	"SK": "Slovakia",
+	"SL": "Sierra Leone",
+	"SM": "San Marino",
+	"SN": "Senegal",
+	"SO": "Somalia",
+	"SR": "Suriname",
+	"SS": "South Sudan",
+	"ST": "Sao Tome and Principe",
+	"SV": "El Salvador",
+	"SX": "Sint Maarten (Dutch part)",
+	"SY": "Syria",
+	"SZ": "Eswatini",
+	"TC": "Turks and Caicos Islands",
+	"TD": "Chad",
+	"TF": "French Southern Territories",
+	"TG": "Togo",
+	"TH": "Thailand",
+	"TJ": "Tajikistan",
+	"TK": "Tokelau",
+	"TL": "Timor-Leste",
+	"TM": "Turkmenistan",
+	"TN": "Tunisia",
+	"TO": "Tonga",
+	"TR": "Türkiye",
+	"TT": "Trinidad and Tobago",
+	"TV": "Tuvalu",
+	"TW": "Taiwan",
+	"TZ": "Tanzania",
+	"UA": "Ukraine",
+	"UG": "Uganda",
+	"UM": "United States Minor Outlying Islands",
+	"US": "United States",
+	"UY": "Uruguay",
+	"UZ": "Uzbekistan",
+	"VA": "Holy See (Vatican City State)",
+	"VC": "Saint Vincent and the Grenadines",
+	"VE": "Venezuela",
+	"VG": "Virgin Islands, British",
+	"VI": "Virgin Islands, U.S.",
+	"VN": "Vietnam",
+	"VU": "Vanuatu",
+	"WF": "Wallis and Futuna",
+	"WS": "Samoa",
+	"YE": "Yemen",
+	"YT": "Mayotte",
+	"ZA": "South Africa",
+	"ZM": "Zambia",
+	"ZW": "Zimbabwe",
+}
@@ -0,0 +1,117 @@
+package veem
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCodesValid(t *testing.T) {
+	tests := []struct {
+		code interface{ Valid() bool }
+		want bool
+	}{
+		{CountryCode("US"), true},
+		{CountryCode("us"), true},
+		{CountryCode("Gb"), true},
+		{CountryCode("XX"), false},
+		{CountryCode(""), false},
+		{CurrencyCode("USD"), true},
+		{CurrencyCode("usd"), true},
+		{CurrencyCode("ABC"), false},
+	}
+	for _, tt := range tests {
+		if got := tt.code.Valid(); got != tt.want {
+			t.Errorf("%v.Valid() = %t, want %t", tt.code, got, tt.want)
+		}
+	}
+	if name := CountryCode("us").Name(); name == "" {
+		t.Error(`CountryCode("us").Name() is empty`)
+	}
+}
+
+func TestMarshalRequest(t *testing.T) {
+	country := CountryCode("ZZ")
+	tests := []struct {
+		name    string
+		v       interface{}
+		wantErr bool
+	}{
+		{"lower case country", &ContactFull{Contact: &Contact{ISOCountryCode: "us"}}, false},
+		{"empty codes", &ContactFull{Contact: &Contact{}}, false},
+		{"unknown country", &ContactFull{Contact: &Contact{ISOCountryCode: "ZZ"}}, true},
+		{"unknown bank currency", &ContactFull{Contact: &Contact{}, BankAccount: &BankAccount{CurrencyCode: "ABC"}}, true},
+		{"patch pointer", &ContactPatch{ISOCountryCode: &country}, true},
+		{"batch", []*DraftPayment{{Amount: &Amount{Currency: "usd"}}, {Amount: &Amount{Currency: "XYZ"}}}, true},
+	}
+	for _, tt := range tests {
+		_, err := marshalRequest(tt.v)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: marshalRequest error = %v, want error %t", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestMarshalRequestUpperCasesCodes(t *testing.T) {
+	payment := &DraftPayment{
+		Amount: &Amount{Currency: "usd", Number: MustParseDecimal("1")},
+		Payee:  &Entity{CountryCode: "gb"},
+	}
+	data, err := marshalRequest(payment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"currency":"USD"`, `"countryCode":"GB"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("payload %s is missing %s", data, want)
+		}
+	}
+	if payment.Amount.Currency != "usd" {
+		t.Error("marshalling changed the caller's payment")
+	}
+	data, err = marshalRequest(&ContactFull{Contact: &Contact{ISOCountryCode: "us"}})
+	if err != nil || !strings.Contains(string(data), `"isoCountryCode":"US"`) {
+		t.Errorf("contact payload = %s, %v, want isoCountryCode US", data, err)
+	}
+}
+
+func TestResponsesAllowUnknownCodes(t *testing.T) {
+	// Veem may support countries the registry does not know, e.g. Kosovo.
+	countries := []*CountryCurrentMap{{Country: "XK", ReceivingCurrencies: []CurrencyCode{"EUR"}}}
+	if _, err := json.Marshal(countries); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "metadata.json")
+	if err := NewMetadata(countries, time.Now()).Save(path); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := LoadMetadata(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := meta.Country("xk"); !ok {
+		t.Error("XK is missing from the loaded metadata")
+	}
+}
+
+func TestCreateRejectsUnknownCodes(t *testing.T) {
+	calls := 0
+	c := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"id":1}`))
+	})
+	ctx := context.Background()
+	if _, err := c.Contacts().Create(ctx, &ContactFull{Contact: &Contact{Email: "a@example.com", ISOCountryCode: "ZZ"}}); err == nil {
+		t.Error("creating a contact in ZZ succeeded, want an error")
+	}
+	if calls != 0 {
+		t.Errorf("the API was called %d times, want 0", calls)
+	}
+	if _, err := c.Contacts().Create(ctx, &ContactFull{Contact: &Contact{Email: "a@example.com", ISOCountryCode: "us"}}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,158 @@
+package veem
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how failed requests are retried. A nil policy
+// disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made for a request,
+	// including the first one. Values less than 2 disable retries.
+	MaxAttempts int
+	// MinBackoff is the delay before the first retry. Each subsequent retry
+	// doubles the delay up to MaxBackoff.
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between attempts, including delays requested
+	// by the API via Retry-After.
+	MaxBackoff time.Duration
+	// Jitter is the fraction (0-1) of each backoff that is randomized to avoid
+	// synchronized retries across clients.
+	Jitter float64
+	// RetryNonIdempotent allows retrying POST requests. The X-REQUEST-ID
+	// header is kept stable across attempts so Veem can de-duplicate them.
+	// Creates sent with an ID from WithRequestID are always eligible for
+	// retries.
+	RetryNonIdempotent bool
+	// OnAttempt, if set, is called after every attempt with its outcome.
+	OnAttempt func(*RetryAttempt)
+}
+
+// DefaultRetryPolicy returns a RetryPolicy suitable for most callers. It only
+// retries idempotent requests and those with a caller-supplied request ID.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 4,
+		MinBackoff:  500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+		Jitter:      0.5,
+	}
+}
+
+// RetryAttempt describes the outcome of a single attempt at a request.
+type RetryAttempt struct {
+	// The request that was sent.
+	Request *http.Request
+	// The attempt number, starting at 1.
+	Attempt int
+	// The HTTP status code of the response, or 0 if none was received.
+	StatusCode int
+	// The error produced by the attempt, if any.
+	Err error
+	// Whether the request will be retried.
+	WillRetry bool
+	// The delay before the next attempt, if WillRetry is true.
+	Backoff time.Duration
+}
+
+// retryableStatuses are the response codes that indicate a transient failure.
+var retryableStatuses = map[int]bool{
+	http.StatusTooManyRequests:     true,
+	http.StatusInternalServerError: true,
+	http.StatusBadGateway:          true,
+	http.StatusServiceUnavailable:  true,
+	http.StatusGatewayTimeout:      true,
+}
+
+// shouldRetry reports whether the given attempt should be retried and how
+// long to wait before doing so. Keyed requests carry a caller-supplied
+// request ID.
+func (p *RetryPolicy) shouldRetry(req *http.Request, keyed bool, attempt int, res *http.Response, err error) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxAttempts {
+		return 0, false
+	}
+	if !p.canRetry(req, keyed) {
+		return 0, false
+	}
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return 0, false
+		}
+		return p.backoff(attempt), true
+	}
+	if !retryableStatuses[res.StatusCode] {
+		return 0, false
+	}
+	if wait, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+		if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+			wait = p.MaxBackoff
+		}
+		return wait, true
+	}
+	return p.backoff(attempt), true
+}
+
+func (p *RetryPolicy) canRetry(req *http.Request, keyed bool) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return keyed || p.RetryNonIdempotent
+}
+
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	d := float64(p.MinBackoff) * math.Pow(2, float64(attempt-1))
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		d -= d * p.Jitter * rand.Float64()
+	}
+	return time.Duration(d)
+}
+
+func (p *RetryPolicy) notify(a *RetryAttempt) {
+	if p != nil && p.OnAttempt != nil {
+		p.OnAttempt(a)
+	}
+}
+
+// parseRetryAfter parses a Retry-After header in either delay-seconds or
+// HTTP-date form.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		wait := time.Until(t)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// sleepContext waits for the given duration or until the context is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,127 @@
+package veem
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"0", 0, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0, true},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v, want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := &RetryPolicy{MinBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+		{10, 5 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := p.backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := p.backoff(1); got < 500*time.Millisecond || got > time.Second {
+			t.Fatalf("backoff with jitter = %v, want between 500ms and 1s", got)
+		}
+	}
+}
+
+func TestCanRetry(t *testing.T) {
+	tests := []struct {
+		method      string
+		keyed       bool
+		nonIdem     bool
+		wantAllowed bool
+	}{
+		{http.MethodGet, false, false, true},
+		{http.MethodPut, false, false, true},
+		{http.MethodDelete, false, false, true},
+		{http.MethodPost, false, false, false},
+		{http.MethodPatch, false, false, false},
+		{http.MethodPost, true, false, true},
+		{http.MethodPost, false, true, true},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(tt.method, "http://example.com", nil)
+		p := &RetryPolicy{RetryNonIdempotent: tt.nonIdem}
+		if got := p.canRetry(req, tt.keyed); got != tt.wantAllowed {
+			t.Errorf("canRetry(%s, keyed=%t, nonIdempotent=%t) = %t, want %t", tt.method, tt.keyed, tt.nonIdem, got, tt.wantAllowed)
+		}
+	}
+}
+
+func TestRetryTransientFailures(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		ctx       context.Context
+		create    bool
+		wantCalls int32
+		wantErr   bool
+	}{
+		{"get is retried", http.MethodGet, context.Background(), false, 3, false},
+		{"post is not retried", http.MethodPost, context.Background(), false, 1, true},
+		{"unkeyed create is not retried", http.MethodPost, context.Background(), true, 1, true},
+		{"keyed post is not retried", http.MethodPost, WithRequestID(context.Background(), "key"), false, 1, true},
+		{"keyed create is retried", http.MethodPost, WithRequestID(context.Background(), "key"), true, 3, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			ids := make(map[string]bool)
+			p := DefaultRetryPolicy()
+			p.MinBackoff = time.Millisecond
+			c := newTestClient(t, &ClientOptions{RetryPolicy: p}, func(w http.ResponseWriter, r *http.Request) {
+				ids[r.Header.Get(requestIDHeader)] = true
+				if atomic.AddInt32(&calls, 1) < 3 {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.Write([]byte(`{"id":1}`))
+			})
+			req, err := c.newRequest(tt.ctx, tt.method, "veem/v1.1/contacts", nil)
+			if tt.create {
+				req, err = c.newCreateRequest(tt.ctx, "veem/v1.1/contacts", nil)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = c.doIntoWithAuth(req, &Contact{})
+			if (err != nil) != tt.wantErr || calls != tt.wantCalls {
+				t.Fatalf("got err %v after %d calls, want error %t after %d", err, calls, tt.wantErr, tt.wantCalls)
+			}
+			if len(ids) != 1 {
+				t.Errorf("request IDs changed between attempts: %v", ids)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package veem
+
+import "fmt"
+
+// PaymentStatus is the state of a payment.
+//
+// Payments start as Drafted, wait in PendingApproval if the account requires
+// approvals, and are then Sent to the payee. Once claimed they move through
+// PendingAuth and Authorized to Complete, or to Failed. Payments may be
+// Cancelled until they are authorized, and Sent payments that are never
+// claimed are eventually Closed. Statuses not listed here are treated as
+// unknown, see CanTransitionTo.
+type PaymentStatus string
+
+const (
+	PaymentDrafted         PaymentStatus = "Drafted"
+	PaymentPendingApproval PaymentStatus = "PendingApproval"
+	PaymentSent            PaymentStatus = "Sent"
+	PaymentPendingAuth     PaymentStatus = "PendingAuth"
+	PaymentAuthorized      PaymentStatus = "Authorized"
+	PaymentComplete        PaymentStatus = "Complete"
+	PaymentCancelled       PaymentStatus = "Cancelled"
+	PaymentClosed          PaymentStatus = "Closed"
+	PaymentFailed          PaymentStatus = "Failed"
+)
+
+var paymentTransitions = map[PaymentStatus][]PaymentStatus{
+	PaymentDrafted:         {PaymentPendingApproval, PaymentSent, PaymentCancelled},
+	PaymentPendingApproval: {PaymentSent, PaymentCancelled},
+	PaymentSent:            {PaymentPendingAuth, PaymentAuthorized, PaymentCancelled, PaymentClosed},
+	PaymentPendingAuth:     {PaymentAuthorized, PaymentCancelled, PaymentFailed},
+	PaymentAuthorized:      {PaymentComplete, PaymentFailed},
+	PaymentComplete:        {},
+	PaymentCancelled:       {},
+	PaymentClosed:          {},
+	PaymentFailed:          {},
+}
+
+// IsKnown reports whether the status is one this package knows about.
+func (s PaymentStatus) IsKnown() bool {
+	_, ok := paymentTransitions[s]
+	return ok
+}
+
+// IsTerminal reports whether the payment can no longer change.
+func (s PaymentStatus) IsTerminal() bool {
+	next, ok := paymentTransitions[s]
+	return ok && len(next) == 0
+}
+
+// IsPendingApproval reports whether the payment is waiting on an approver
+// in the account.
+func (s PaymentStatus) IsPendingApproval() bool { return s == PaymentPendingApproval }
+
+// CanCancel reports whether the payment can still be cancelled.
+func (s PaymentStatus) CanCancel() bool {
+	switch s {
+	case PaymentDrafted, PaymentPendingApproval, PaymentSent, PaymentPendingAuth:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo reports whether a payment may move from s to next. It
+// returns true if either status is unknown, so that statuses added to the
+// API later are tolerated.
+func (s PaymentStatus) CanTransitionTo(next PaymentStatus) bool {
+	return canTransition(paymentTransitions, s, next)
+}
+
+// InvoiceStatus is the state of an invoice.
+//
+// Invoices move from Drafted to Sent, then through PendingAuth and
+// Authorized to Complete once paid. They may be Cancelled until they are
+// authorized, and unpaid Sent invoices are eventually Closed.
+type InvoiceStatus string
+
+const (
+	InvoiceDrafted     InvoiceStatus = "Drafted"
+	InvoiceSent        InvoiceStatus = "Sent"
+	InvoicePendingAuth InvoiceStatus = "PendingAuth"
+	InvoiceAuthorized  InvoiceStatus = "Authorized"
+	InvoiceComplete    InvoiceStatus = "Complete"
+	InvoiceCancelled   InvoiceStatus = "Cancelled"
+	InvoiceClosed      InvoiceStatus = "Closed"
+)
+
+var invoiceTransitions = map[InvoiceStatus][]InvoiceStatus{
+	InvoiceDrafted:     {InvoiceSent, InvoiceCancelled},
+	InvoiceSent:        {InvoicePendingAuth, InvoiceAuthorized, InvoiceCancelled, InvoiceClosed},
+	InvoicePendingAuth: {InvoiceAuthorized, InvoiceCancelled},
+	InvoiceAuthorized:  {InvoiceComplete},
+	InvoiceComplete:    {},
+	InvoiceCancelled:   {},
+	InvoiceClosed:      {},
+}
+
+// IsKnown reports whether the status is one this package knows about.
+func (s InvoiceStatus) IsKnown() bool {
+	_, ok := invoiceTransitions[s]
+	return ok
+}
+
+// IsTerminal reports whether the invoice can no longer change.
+func (s InvoiceStatus) IsTerminal() bool {
+	next, ok := invoiceTransitions[s]
+	return ok && len(next) == 0
+}
+
+// IsPendingApproval reports whether the invoice is waiting on the payer to
+// authorize the payment.
+func (s InvoiceStatus) IsPendingApproval() bool { return s == InvoicePendingAuth }
+
+// CanCancel reports whether the invoice can still be cancelled.
+func (s InvoiceStatus) CanCancel() bool {
+	switch s {
+	case InvoiceDrafted, InvoiceSent, InvoicePendingAuth:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo reports whether an invoice may move from s to next. It
+// returns true if either status is unknown.
+func (s InvoiceStatus) CanTransitionTo(next InvoiceStatus) bool {
+	return canTransition(invoiceTransitions, s, next)
+}
+
+// BatchStatus is the state of a batch operation or one of its items.
+//
+// Batches move from Pending to InProgress and then to Complete or Failed.
+type BatchStatus string
+
+const (
+	BatchPending    BatchStatus = "Pending"
+	BatchInProgress BatchStatus = "InProgress"
+	BatchComplete   BatchStatus = "Complete"
+	BatchFailed     BatchStatus = "Failed"
+)
+
+var batchTransitions = map[BatchStatus][]BatchStatus{
+	BatchPending:    {BatchInProgress, BatchComplete, BatchFailed},
+	BatchInProgress: {BatchComplete, BatchFailed},
+	BatchComplete:   {},
+	BatchFailed:     {},
+}
+
+// IsKnown reports whether the status is one this package knows about.
+func (s BatchStatus) IsKnown() bool {
+	_, ok := batchTransitions[s]
+	return ok
+}
+
+// IsTerminal reports whether the batch or item has finished processing.
+func (s BatchStatus) IsTerminal() bool {
+	next, ok := batchTransitions[s]
+	return ok && len(next) == 0
+}
+
+// CanTransitionTo reports whether a batch may move from s to next. It
+// returns true if either status is unknown.
+func (s BatchStatus) CanTransitionTo(next BatchStatus) bool {
+	return canTransition(batchTransitions, s, next)
+}
+
+// TransitionError describes a status change that is not allowed by the
+// documented lifecycle.
+type TransitionError struct {
+	From, To string
+}
+
+// Error implements the error interface.
+func (t *TransitionError) Error() string {
+	return fmt.Sprintf("invalid status transition from %s to %s", t.From, t.To)
+}
+
+// ValidatePaymentTransition returns a *TransitionError if a payment observed
+// in status from cannot have moved to status to.
+func ValidatePaymentTransition(from, to PaymentStatus) error {
+	if !from.CanTransitionTo(to) {
+		return &TransitionError{From: string(from), To: string(to)}
+	}
+	return nil
+}
+
+// ValidateInvoiceTransition returns a *TransitionError if an invoice
+// observed in status from cannot have moved to status to.
+func ValidateInvoiceTransition(from, to InvoiceStatus) error {
+	if !from.CanTransitionTo(to) {
+		return &TransitionError{From: string(from), To: string(to)}
+	}
+	return nil
+}
+
+// canTransition reports whether next is reachable from s in the transition
+// table, directly or through intermediate states that may not have been
+// observed. Unknown statuses are always allowed.
+func canTransition[S comparable](table map[S][]S, s, next S) bool {
+	if _, ok := table[s]; !ok {
+		return true
+	}
+	if _, ok := table[next]; !ok {
+		return true
+	}
+	seen := map[S]bool{s: true}
+	queue := []S{s}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, n := range table[cur] {
+			if seen[n] {
+				continue
+			}
+			seen[n] = true
+			queue = append(queue, n)
+		}
+	}
+	return seen[next]
+}
@@ -0,0 +1,153 @@
+package veem
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// tokenExpiryLeeway is how long before expiry a token is considered
+	// unusable and is refreshed before sending a request.
+	tokenExpiryLeeway = time.Minute
+	// tokenRefreshWindow is how long before expiry a token is refreshed in
+	// the background while it is still handed out to callers.
+	tokenRefreshWindow = 5 * time.Minute
+	// tokenFetchTimeout bounds each refresh. Refreshes are shared between
+	// callers so they are not tied to any single caller's context, instead a
+	// refresh is cancelled once every caller waiting on it has given up.
+	tokenFetchTimeout = 30 * time.Second
+	// tokenRetryBackoff is how long to wait after a failed background
+	// refresh before trying again, while the current token is still usable.
+	tokenRetryBackoff = 30 * time.Second
+)
+
+// tokenSource hands out access tokens, refreshing them before they expire.
+// Concurrent refreshes are collapsed into a single call to fetch.
+type tokenSource struct {
+	fetch func(ctx context.Context) (*AccessTokenResponse, error)
+
+	mu       sync.Mutex
+	token    *AccessTokenResponse
+	last     *AccessTokenResponse
+	rejected string
+	refresh  *tokenRefresh
+	// retryAt is when a background refresh may be tried again after one
+	// failed.
+	retryAt time.Time
+}
+
+// tokenRefresh is an in-flight call to fetch shared by all waiters.
+type tokenRefresh struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+	token  *AccessTokenResponse
+	err    error
+	// waiters is the number of callers blocked on the refresh and
+	// background is set if the current token is still usable while it runs.
+	// Both are guarded by the tokenSource mutex.
+	waiters    int
+	background bool
+}
+
+func newTokenSource(fetch func(ctx context.Context) (*AccessTokenResponse, error)) *tokenSource {
+	return &tokenSource{fetch: fetch}
+}
+
+// Token returns a usable access token, fetching a new one if there is none or
+// the current one is about to expire. A token close to expiry is refreshed in
+// the background, and a failed background refresh is only retried after
+// tokenRetryBackoff.
+func (t *tokenSource) Token(ctx context.Context) (*AccessTokenResponse, error) {
+	t.mu.Lock()
+	tok := t.token
+	switch {
+	case tok == nil || time.Now().Add(tokenExpiryLeeway).After(tok.ExpiresAt):
+		r := t.startRefresh()
+		r.waiters++
+		t.mu.Unlock()
+		return t.wait(ctx, r)
+	case time.Now().Add(tokenRefreshWindow).After(tok.ExpiresAt) && !time.Now().Before(t.retryAt):
+		t.startRefresh().background = true
+	}
+	t.mu.Unlock()
+	return tok, nil
+}
+
+// Last returns the last token retrieved, even if it has since been
+// invalidated, without refreshing it.
+func (t *tokenSource) Last() *AccessTokenResponse {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.last
+}
+
+// set replaces the current token.
+func (t *tokenSource) set(tok *AccessTokenResponse) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.token, t.last = tok, tok
+}
+
+// Invalidate discards the given token if it is still the current one, so the
+// next call to Token fetches a new one. It is used when the API rejects a
+// token before its expiry.
+func (t *tokenSource) Invalidate(tok *AccessTokenResponse) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rejected = tok.AccessToken
+	if t.token == tok {
+		t.token = nil
+	}
+}
+
+// Rejected reports whether the API has rejected the given token.
+func (t *tokenSource) Rejected(tok *AccessTokenResponse) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.rejected != "" && t.rejected == tok.AccessToken
+}
+
+// startRefresh returns the in-flight refresh, starting one if needed. It must
+// be called with mu held.
+func (t *tokenSource) startRefresh() *tokenRefresh {
+	if t.refresh != nil {
+		return t.refresh
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), tokenFetchTimeout)
+	r := &tokenRefresh{done: make(chan struct{}), cancel: cancel}
+	t.refresh = r
+	go func() {
+		defer cancel()
+		tok, err := t.fetch(ctx)
+		t.mu.Lock()
+		if err == nil {
+			t.token, t.last = tok, tok
+			t.retryAt = time.Time{}
+		} else {
+			t.retryAt = time.Now().Add(tokenRetryBackoff)
+		}
+		t.refresh = nil
+		t.mu.Unlock()
+		r.token, r.err = tok, err
+		close(r.done)
+	}()
+	return r
+}
+
+// wait blocks until the refresh completes or ctx is done. Unless it is a
+// background refresh, the refresh is cancelled when its last waiter gives up.
+func (t *tokenSource) wait(ctx context.Context, r *tokenRefresh) (*AccessTokenResponse, error) {
+	select {
+	case <-r.done:
+		return r.token, r.err
+	case <-ctx.Done():
+		t.mu.Lock()
+		r.waiters--
+		if r.waiters == 0 && !r.background {
+			r.cancel()
+		}
+		t.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,75 @@
+package veem
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTokenSourceSingleFlight(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	ts := newTokenSource(func(ctx context.Context) (*AccessTokenResponse, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return &AccessTokenResponse{AccessToken: "a", ExpiresAt: time.Now().Add(time.Hour)}, nil
+	})
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if tok, err := ts.Token(context.Background()); err != nil || tok.AccessToken != "a" {
+				t.Errorf("Token() = %v, %v", tok, err)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if calls != 1 {
+		t.Errorf("fetch called %d times, want 1", calls)
+	}
+}
+
+func TestTokenSourceCancelledWhenWaitersLeave(t *testing.T) {
+	cancelled := make(chan struct{})
+	ts := newTokenSource(func(ctx context.Context) (*AccessTokenResponse, error) {
+		<-ctx.Done()
+		close(cancelled)
+		return nil, ctx.Err()
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := ts.Token(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Token() error = %v, want context.DeadlineExceeded", err)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("shared fetch was not cancelled after its only waiter left")
+	}
+}
+
+func TestTokenSourceBackgroundRefreshBackoff(t *testing.T) {
+	var calls int32
+	ts := newTokenSource(func(ctx context.Context) (*AccessTokenResponse, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, errors.New("oauth is down")
+	})
+	// The token is usable but inside the refresh window.
+	current := &AccessTokenResponse{AccessToken: "a", ExpiresAt: time.Now().Add(tokenRefreshWindow / 2)}
+	ts.set(current)
+	for i := 0; i < 5; i++ {
+		if tok, err := ts.Token(context.Background()); err != nil || tok != current {
+			t.Fatalf("Token() = %v, %v, want the current token", tok, err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("fetch called %d times after a failed background refresh, want 1", n)
+	}
+}
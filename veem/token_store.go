@@ -0,0 +1,178 @@
+package veem
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// TokenStore persists access tokens so they can be shared between clients,
+// including clients in other processes. Tokens are stored under a key
+// identifying the credentials and API they were issued for.
+type TokenStore interface {
+	// Load returns the token stored under key, or nil if there is none.
+	Load(ctx context.Context, key string) (*AccessTokenResponse, error)
+	// Save stores the token under key.
+	Save(ctx context.Context, key string, tok *AccessTokenResponse) error
+	// Lock acquires an exclusive lock on key so only one user of the store
+	// refreshes the token at a time. The returned function releases it.
+	Lock(ctx context.Context, key string) (unlock func(), err error)
+}
+
+// NewMemoryTokenStore returns a TokenStore that keeps tokens in memory. It
+// can be shared by clients in the same process.
+func NewMemoryTokenStore() TokenStore {
+	return &memoryTokenStore{tokens: make(map[string]*AccessTokenResponse), locks: make(map[string]chan struct{})}
+}
+
+type memoryTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]*AccessTokenResponse
+	locks  map[string]chan struct{}
+}
+
+func (m *memoryTokenStore) Load(ctx context.Context, key string) (*AccessTokenResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.tokens[key], nil
+}
+
+func (m *memoryTokenStore) Save(ctx context.Context, key string, tok *AccessTokenResponse) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tokens[key] = tok
+	return nil
+}
+
+func (m *memoryTokenStore) Lock(ctx context.Context, key string) (func(), error) {
+	m.mu.Lock()
+	l, ok := m.locks[key]
+	if !ok {
+		l = make(chan struct{}, 1)
+		m.locks[key] = l
+	}
+	m.mu.Unlock()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case l <- struct{}{}:
+		return func() { <-l }, nil
+	}
+}
+
+// NewFileTokenStore returns a TokenStore that keeps tokens in the file at
+// path, encrypted with AES-GCM, and guarded by a lock file next to it so it
+// can be shared between processes. The encryption key must be 16, 24 or 32
+// bytes long.
+func NewFileTokenStore(path string, encryptionKey []byte) (TokenStore, error) {
+	if len(encryptionKey) == 0 {
+		return nil, errors.New("an encryption key is required, use NewPlaintextFileTokenStore to store tokens unencrypted")
+	}
+	block, err := aes.NewCipher(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &fileTokenStore{path: path, aead: aead}, nil
+}
+
+// NewPlaintextFileTokenStore is like NewFileTokenStore but writes tokens to
+// disk unencrypted. Anyone who can read the file can act as the client, so
+// only use it where the file is otherwise protected.
+func NewPlaintextFileTokenStore(path string) TokenStore {
+	return &fileTokenStore{path: path}
+}
+
+type fileTokenStore struct {
+	path string
+	aead cipher.AEAD
+}
+
+func (f *fileTokenStore) Load(ctx context.Context, key string) (*AccessTokenResponse, error) {
+	tokens, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+	return tokens[key], nil
+}
+
+// Save is expected to be called with the lock for key held, which also
+// guards the rest of the file against concurrent writers.
+func (f *fileTokenStore) Save(ctx context.Context, key string, tok *AccessTokenResponse) error {
+	tokens, err := f.read()
+	if err != nil {
+		return err
+	}
+	tokens[key] = tok
+	return f.write(tokens)
+}
+
+// Lock locks the whole file rather than only key.
+func (f *fileTokenStore) Lock(ctx context.Context, key string) (func(), error) {
+	return lockFile(ctx, f.path+".lock")
+}
+
+func (f *fileTokenStore) read() (map[string]*AccessTokenResponse, error) {
+	tokens := make(map[string]*AccessTokenResponse)
+	data, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return tokens, nil
+		}
+		return nil, err
+	}
+	if f.aead != nil {
+		size := f.aead.NonceSize()
+		if len(data) < size {
+			return nil, errors.New("token store is corrupt or not encrypted")
+		}
+		if data, err = f.aead.Open(nil, data[:size], data[size:], nil); err != nil {
+			return nil, err
+		}
+	}
+	return tokens, json.Unmarshal(data, &tokens)
+}
+
+func (f *fileTokenStore) write(tokens map[string]*AccessTokenResponse) error {
+	data, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	if f.aead != nil {
+		nonce := make([]byte, f.aead.NonceSize())
+		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+			return err
+		}
+		data = f.aead.Seal(nonce, nonce, data, nil)
+	}
+	return writeFileAtomic(f.path, data)
+}
+
+// writeFileAtomic replaces the file at path with data, so that readers never
+// see a partial write.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
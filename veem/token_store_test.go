@@ -0,0 +1,70 @@
+package veem
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileTokenStore(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	tests := []struct {
+		name      string
+		open      func(path string) (TokenStore, error)
+		encrypted bool
+	}{
+		{"encrypted", func(path string) (TokenStore, error) { return NewFileTokenStore(path, key) }, true},
+		{"plaintext", func(path string) (TokenStore, error) { return NewPlaintextFileTokenStore(path), nil }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "tokens")
+			store, err := tt.open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+			unlock, err := store.Lock(ctx, "k")
+			if err != nil {
+				t.Fatal(err)
+			}
+			tok := &AccessTokenResponse{AccessToken: "secret-token", ExpiresAt: time.Now().Add(time.Hour).Round(0)}
+			if err := store.Save(ctx, "k", tok); err != nil {
+				t.Fatal(err)
+			}
+			unlock()
+			got, err := store.Load(ctx, "k")
+			if err != nil || got == nil || got.AccessToken != tok.AccessToken || !got.ExpiresAt.Equal(tok.ExpiresAt) {
+				t.Fatalf("Load() = %+v, %v, want %+v", got, err, tok)
+			}
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if contains := bytes.Contains(data, []byte("secret-token")); contains == tt.encrypted {
+				t.Errorf("token readable in file = %t, want %t", contains, !tt.encrypted)
+			}
+		})
+	}
+}
+
+func TestFileTokenStoreKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens")
+	if _, err := NewFileTokenStore(path, nil); err == nil {
+		t.Error("NewFileTokenStore with no key succeeded, want an error")
+	}
+	if _, err := NewFileTokenStore(path, []byte("short")); err == nil {
+		t.Error("NewFileTokenStore with an invalid key succeeded, want an error")
+	}
+	store, _ := NewFileTokenStore(path, []byte("0123456789abcdef"))
+	if err := store.Save(context.Background(), "k", &AccessTokenResponse{AccessToken: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	other, _ := NewFileTokenStore(path, []byte("fedcba9876543210"))
+	if _, err := other.Load(context.Background(), "k"); err == nil {
+		t.Error("Load with the wrong key succeeded, want an error")
+	}
+}
@@ -1,6 +1,7 @@
 package veem
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,7 +10,6 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
-	"time"
 
 	"github.com/google/uuid"
 )
@@ -22,28 +22,37 @@ func mustParseURL(u string) *url.URL {
 	return p
 }
 
-func parseAPIError(body []byte) error {
+// parseAPIError builds an *APIError from an error response, or a
+// *ValidationError if it names rejected fields. Bodies that are not JSON
+// become the Message.
+func parseAPIError(res *http.Response, body []byte) error {
 	err := &APIError{}
 	if merr := json.Unmarshal(body, err); merr != nil {
-		return errors.New(string(body))
+		err = &APIError{ErrorType: http.StatusText(res.StatusCode), Message: string(body)}
+	}
+	err.StatusCode = res.StatusCode
+	err.RawBody = body
+	if res.Request != nil {
+		err.RequestID = res.Request.Header.Get(requestIDHeader)
+		err.Endpoint = fmt.Sprintf("%s %s", res.Request.Method, res.Request.URL.Path)
+	}
+	if len(err.FieldErrors) > 0 {
+		return &ValidationError{APIError: err}
 	}
 	return err
 }
 
-func (c *client) newRequest(method string, endpoint string, body io.Reader) (*http.Request, error) {
+func (c *client) newRequest(ctx context.Context, method string, endpoint string, body io.Reader) (*http.Request, error) {
 	url := fmt.Sprintf("%s/%s", c.apiURL.String(), endpoint)
-	return http.NewRequest(method, url, body)
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", c.opts.userAgent())
+	return req, nil
 }
 
 func (c *client) doWithAuth(req *http.Request, acceptType string) (io.ReadCloser, error) {
-	if time.Now().Add(-time.Minute).After(c.token.ExpiresAt) {
-		var err error
-		c.token, err = c.getAccessToken()
-		if err != nil {
-			return nil, err
-		}
-	}
-	req.Header.Add("Authorization", fmt.Sprintf("%s %s", strings.ToTitle(c.token.TokenType), c.token.AccessToken))
 	if acceptType == "" {
 		acceptType = "application/json"
 	}
@@ -51,40 +60,138 @@ func (c *client) doWithAuth(req *http.Request, acceptType string) (io.ReadCloser
 	if req.Header.Get("Content-Type") == "" {
 		req.Header.Add("Content-Type", "application/json")
 	}
-	return c.do(req)
+	return c.doAuthorized(req)
 }
 
 func (c *client) doIntoWithAuth(req *http.Request, out interface{}) error {
-	if time.Now().Add(-time.Minute).After(c.token.ExpiresAt) {
-		var err error
-		c.token, err = c.getAccessToken()
-		if err != nil {
-			return err
-		}
-	}
-	if req.Header.Get("Content-Type") == "" {
-		req.Header.Add("Content-Type", "application/json")
+	res, err := c.doWithAuth(req, "application/json")
+	if err != nil {
+		return err
 	}
-	req.Header.Add("Authorization", fmt.Sprintf("%s %s", strings.ToTitle(c.token.TokenType), c.token.AccessToken))
-	req.Header.Add("Accept", "application/json")
-	return c.doInto(req, out)
+	return decodeInto(res, out)
 }
 
-func (c *client) do(req *http.Request) (io.ReadCloser, error) {
-	req.Header.Add("X-REQUEST-ID", uuid.New().String())
-	res, err := c.client.Do(req)
+// doAuthorized sends the request with the current access token. If the API
+// rejects the token it is refreshed and the request is sent once more.
+func (c *client) doAuthorized(req *http.Request) (io.ReadCloser, error) {
+	tok, err := c.tokens.Token(req.Context())
 	if err != nil {
 		return nil, err
 	}
-	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
-		defer res.Body.Close()
-		body, err := ioutil.ReadAll(res.Body)
+	setAuthorization(req, tok)
+	res, err := c.do(req)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		return res, err
+	}
+	if req.GetBody == nil && req.Body != nil && req.Body != http.NoBody {
+		return nil, err
+	}
+	c.tokens.Invalidate(tok)
+	if tok, err = c.tokens.Token(req.Context()); err != nil {
+		return nil, err
+	}
+	if req, err = rewindRequest(req, 2); err != nil {
+		return nil, err
+	}
+	setAuthorization(req, tok)
+	return c.do(req)
+}
+
+func setAuthorization(req *http.Request, tok *AccessTokenResponse) {
+	req.Header.Set("Authorization", fmt.Sprintf("%s %s", strings.ToTitle(tok.TokenType), tok.AccessToken))
+}
+
+const requestIDHeader = "X-REQUEST-ID"
+
+func (c *client) do(req *http.Request) (io.ReadCloser, error) {
+	// Requests carrying a caller-supplied ID are safe to retry and, when a
+	// ledger is configured, are only ever sent once successfully.
+	keyed := false
+	if req.Header.Get(requestIDHeader) == "" {
+		id, ok := requestIDFor(req)
+		if !ok {
+			id = uuid.New().String()
+		}
+		req.Header.Set(requestIDHeader, id)
+		keyed = ok
+	}
+	ledger := c.opts.IdempotencyLedger
+	if !keyed {
+		ledger = nil
+	}
+	if ledger != nil {
+		done, err := ledger.Succeeded(req.Context(), req.Header.Get(requestIDHeader))
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return nil, ErrDuplicateRequest
+		}
+	}
+	policy := c.opts.RetryPolicy
+	for attempt := 1; ; attempt++ {
+		r, err := rewindRequest(req, attempt)
 		if err != nil {
 			return nil, err
 		}
-		return nil, parseAPIError(body)
+		res, err := c.client.Do(r)
+		if err == nil && isSuccess(res.StatusCode) {
+			policy.notify(&RetryAttempt{Request: r, Attempt: attempt, StatusCode: res.StatusCode})
+			if ledger != nil {
+				if err := ledger.MarkSucceeded(req.Context(), req.Header.Get(requestIDHeader)); err != nil {
+					res.Body.Close()
+					return nil, fmt.Errorf("request succeeded but could not be recorded in the idempotency ledger: %w", err)
+				}
+			}
+			return res.Body, nil
+		}
+		wait, retry := policy.shouldRetry(req, keyed, attempt, res, err)
+		status := 0
+		if err == nil {
+			status = res.StatusCode
+			err = readAPIError(res)
+		}
+		policy.notify(&RetryAttempt{Request: r, Attempt: attempt, StatusCode: status, Err: err, WillRetry: retry, Backoff: wait})
+		if !retry {
+			return nil, err
+		}
+		if err := sleepContext(req.Context(), wait); err != nil {
+			return nil, err
+		}
 	}
-	return res.Body, nil
+}
+
+func isSuccess(status int) bool {
+	switch status {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return true
+	}
+	return false
+}
+
+// rewindRequest returns the request to send for the given attempt. Retries
+// get a fresh copy of the body.
+func rewindRequest(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 1 || req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	r := req.Clone(req.Context())
+	r.Body = body
+	return r, nil
+}
+
+func readAPIError(res *http.Response) error {
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	return parseAPIError(res, body)
 }
 
 func (c *client) doInto(req *http.Request, out interface{}) error {
@@ -92,6 +199,10 @@ func (c *client) doInto(req *http.Request, out interface{}) error {
 	if err != nil {
 		return err
 	}
+	return decodeInto(res, out)
+}
+
+func decodeInto(res io.ReadCloser, out interface{}) error {
 	defer res.Close()
 	body, err := ioutil.ReadAll(res)
 	if err != nil {
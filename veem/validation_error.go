@@ -0,0 +1,113 @@
+package veem
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldError describes a single field rejected by the API.
+type FieldError struct {
+	// The JSON path of the field, e.g. "bankAccount.iban".
+	Field string `json:"field"`
+	// Why the field was rejected.
+	Message string `json:"message"`
+	// A machine readable code for the failure, if present.
+	Code string `json:"code,omitempty"`
+	// The value that was rejected, if present.
+	RejectedValue interface{} `json:"rejectedValue,omitempty"`
+}
+
+// ValidationError is returned when the API rejects specific fields of a
+// request. It matches ErrValidation with errors.Is.
+type ValidationError struct {
+	*APIError
+}
+
+// Error implements the error interface.
+func (v *ValidationError) Error() string {
+	fields := make([]string, len(v.FieldErrors))
+	for i, f := range v.FieldErrors {
+		fields[i] = fmt.Sprintf("%s: %s", f.Field, f.Message)
+	}
+	return fmt.Sprintf("%s [%s]", v.APIError.Error(), strings.Join(fields, ", "))
+}
+
+// Is reports whether target is ErrValidation or matches the underlying
+// *APIError.
+func (v *ValidationError) Is(target error) bool {
+	return target == ErrValidation || v.APIError.Is(target)
+}
+
+// Unwrap returns the underlying *APIError.
+func (v *ValidationError) Unwrap() error { return v.APIError }
+
+// Field returns the errors for the field at the given JSON path.
+func (v *ValidationError) Field(path string) []*FieldError {
+	var out []*FieldError
+	for _, f := range v.FieldErrors {
+		if strings.EqualFold(f.Field, path) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// StructField resolves the JSON path of the field error to the path of the
+// Go field it was read from in v, e.g. "payee.email" on a *DraftPayment is
+// "Payee.Email". Array indices such as "attachments[0].name" are kept. It
+// returns false if the path does not exist in v.
+func (f *FieldError) StructField(v interface{}) (string, bool) {
+	t := reflect.TypeOf(v)
+	var out []string
+	for _, part := range strings.Split(f.Field, ".") {
+		name, index := part, ""
+		if i := strings.Index(part, "["); i >= 0 {
+			name, index = part[:i], part[i:]
+		}
+		for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return "", false
+		}
+		field, ok := fieldByJSONName(t, name)
+		if !ok {
+			return "", false
+		}
+		out = append(out, field.Name+index)
+		t = field.Type
+	}
+	return strings.Join(out, "."), true
+}
+
+// fieldByJSONName finds the field of t encoded under the given JSON name,
+// searching inline embedded structs.
+func fieldByJSONName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("json"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+		if field.Anonymous && (tag == "" || field.Tag.Get("json") == ",inline") {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if f, ok := fieldByJSONName(ft, name); ok {
+					return f, true
+				}
+			}
+			continue
+		}
+		if tag == "" {
+			tag = field.Name
+		}
+		if strings.EqualFold(tag, name) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}